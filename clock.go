@@ -0,0 +1,226 @@
+package sync
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Clock provides the current time and timers, so time based features can be controlled in tests
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+
+	// NewTimer returns a Timer which sends the current time on its channel after the duration elapses
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single event timer created by a Clock
+type Timer interface {
+	// C returns the channel on which the time is sent when the timer fires
+	C() <-chan time.Time
+
+	// Stop prevents the timer from firing, returning false if it already fired or was stopped
+	Stop() bool
+}
+
+// SystemClock returns a Clock using the system time
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+type systemTimer struct {
+	*time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// withClockTimeout returns a context like context.WithTimeout, which is canceled once the duration elapses on
+// the clock
+func withClockTimeout(parent context.Context, clock Clock, d time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := clock.(systemClock); ok {
+		return context.WithTimeout(parent, d)
+	}
+	ctx, cancel := context.WithCancelCause(parent)
+	timer := clock.NewTimer(d)
+	go func() {
+		defer timer.Stop()
+		select {
+		case <-timer.C():
+			cancel(context.DeadlineExceeded)
+		case <-ctx.Done():
+		}
+	}()
+	return timeoutContext{ctx}, func() {
+		cancel(context.Canceled)
+	}
+}
+
+// timeoutContext reports context.DeadlineExceeded from Err when canceled due to a timeout, as a context returned
+// by context.WithTimeout does
+type timeoutContext struct {
+	context.Context
+}
+
+func (c timeoutContext) Err() error {
+	err := c.Context.Err()
+	if err != nil && context.Cause(c.Context) == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+	return err
+}
+
+// FakeClock is a Clock which only changes when advanced, so time based features can be tested deterministically.
+// The zero value starts at the zero time and is ready to use
+type FakeClock struct {
+	lock    sync.Mutex
+	now     time.Time
+	timers  []*fakeTimer
+	changed chan struct{}
+}
+
+var _ Clock = (*FakeClock)(nil)
+
+// NewFakeClock returns a FakeClock set to the provided time
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+// NewTimer returns a Timer which fires when the clock is advanced by at least the duration, a duration <= 0
+// fires immediately
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	t := &fakeTimer{
+		clock: c,
+		at:    c.now.Add(d),
+		c:     make(chan time.Time, 1),
+	}
+	if d <= 0 {
+		t.c <- c.now
+		return t
+	}
+	c.timers = append(c.timers, t)
+	c.notify()
+	return t
+}
+
+// Advance moves the clock forward by the duration, firing timers which become due in the order they are due
+func (c *FakeClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	c.set(c.now.Add(d))
+	c.lock.Unlock()
+}
+
+// Set moves the clock to the provided time, firing timers which become due; the clock does not move backward
+func (c *FakeClock) Set(now time.Time) {
+	c.lock.Lock()
+	if now.After(c.now) {
+		c.set(now)
+	}
+	c.lock.Unlock()
+}
+
+// Timers returns the number of timers which have not fired or been stopped
+func (c *FakeClock) Timers() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.timers)
+}
+
+// BlockUntil waits until at least n timers are pending, such as when goroutines being tested are waiting for
+// the clock to advance, returning the context error if the context is canceled first
+func (c *FakeClock) BlockUntil(ctx context.Context, n int) error {
+	for {
+		c.lock.Lock()
+		if len(c.timers) >= n {
+			c.lock.Unlock()
+			return nil
+		}
+		if c.changed == nil {
+			c.changed = make(chan struct{})
+		}
+		changed := c.changed
+		c.lock.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// set moves the clock to the time and fires due timers, must be called with the lock held
+func (c *FakeClock) set(now time.Time) {
+	c.now = now
+	slices.SortStableFunc(c.timers, func(a, b *fakeTimer) int {
+		return a.at.Compare(b.at)
+	})
+	fired := 0
+	for _, t := range c.timers {
+		if t.at.After(now) {
+			break
+		}
+		t.c <- now
+		fired++
+	}
+	if fired > 0 {
+		c.timers = slices.Delete(c.timers, 0, fired)
+		c.notify()
+	}
+}
+
+// notify wakes calls to BlockUntil when the pending timers change, must be called with the lock held
+func (c *FakeClock) notify() {
+	if c.changed != nil {
+		close(c.changed)
+		c.changed = nil
+	}
+}
+
+// stop removes the timer from the pending timers, returning false if it was not pending
+func (c *FakeClock) stop(t *fakeTimer) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	i := slices.Index(c.timers, t)
+	if i < 0 {
+		return false
+	}
+	c.timers = slices.Delete(c.timers, i, i+1)
+	c.notify()
+	return true
+}
+
+type fakeTimer struct {
+	clock *FakeClock
+	at    time.Time
+	c     chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	return t.clock.stop(t)
+}
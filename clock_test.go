@@ -0,0 +1,62 @@
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FakeClock(t *testing.T) {
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	require.Equal(t, start, clock.Now())
+
+	later := clock.NewTimer(2 * time.Second)
+	sooner := clock.NewTimer(time.Second)
+	stopped := clock.NewTimer(time.Second)
+	require.Equal(t, 3, clock.Timers())
+	require.True(t, stopped.Stop())
+	require.False(t, stopped.Stop())
+
+	clock.Advance(time.Second)
+	require.Equal(t, start.Add(time.Second), clock.Now())
+	require.Equal(t, start.Add(time.Second), <-sooner.C())
+	require.False(t, sooner.Stop())
+	select {
+	case <-later.C():
+		t.Fatal("timer fired early")
+	case <-stopped.C():
+		t.Fatal("stopped timer fired")
+	default:
+	}
+
+	// does not move backward
+	clock.Set(start)
+	require.Equal(t, start.Add(time.Second), clock.Now())
+
+	clock.Set(start.Add(time.Minute))
+	require.Equal(t, start.Add(time.Minute), <-later.C())
+	require.Zero(t, clock.Timers())
+
+	// fires immediately
+	immediate := clock.NewTimer(0)
+	require.Equal(t, start.Add(time.Minute), <-immediate.C())
+}
+
+func Test_FakeClockBlockUntil(t *testing.T) {
+	var clock FakeClock
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, clock.BlockUntil(ctx, 1), context.DeadlineExceeded)
+
+	fired := make(chan time.Time)
+	go func() {
+		fired <- <-clock.NewTimer(time.Hour).C()
+	}()
+	require.NoError(t, clock.BlockUntil(context.Background(), 1))
+	clock.Advance(time.Hour)
+	require.Equal(t, time.Time{}.Add(time.Hour), <-fired)
+}
@@ -6,51 +6,322 @@ import (
 	"iter"
 	"runtime/debug"
 	"sync"
+	"time"
 )
 
 // Collect iterates over the provided iterator, executing the processor in parallel to map each incoming value to a result.
 // The accumulator is used to apply the results, with an exclusive lock; accumulator will never execute in parallel.
 // All errors returned from processor functions will be joined with errors.Join as the returned error. Panics are also
-// captured as errors from processor and accumulator functions
+// captured as errors from processor and accumulator functions. Each error is an ItemError identifying the value.
+// Values the executor does not execute, such as when it has been closed, are reported as an ItemError with the
+// reason, such as ErrExecutorClosed, when the executor is a SkipExecutor, as all executors in this package are.
+// When the context is canceled, Collect returns without waiting for executing processors, and their results are
+// discarded; the accumulator is never called after Collect returns. Use CollectWith to find the unprocessed values
 func Collect[From, To any](ctx *context.Context, executorName string, iterator iter.Seq[From], processor func(From) (To, error), accumulator func(From, To)) error {
 	if processor == nil {
 		panic("no processor provided to Collect")
 	}
+	return CollectWith(ctx, executorName, iterator, func(_ context.Context, value From) (To, error) {
+		return processor(value)
+	}, accumulator)
+}
+
+// CollectOption configures the behavior of CollectWith. CollectOrdered and CollectSeq do not accept options, so
+// reporting unprocessed values and waiting for a grace period are only available with CollectWith
+type CollectOption func(*collectConfig)
+
+type collectConfig struct {
+	maxErrors int
+	ignore    func(error) bool
+	grace     time.Duration
+	report    *CollectReport
+}
+
+// CollectReport describes which values were processed by CollectWith, so a canceled call can be resumed
+type CollectReport struct {
+	// Iterated is the number of values taken from the iterator, values at this index and after were not iterated
+	Iterated int
+
+	// Unprocessed is the indexes of values which were iterated but not processed, in order, such as when canceled
+	// before executing, or when still executing after the context was canceled. Their results, if any, were not
+	// passed to the accumulator
+	Unprocessed []int
+}
+
+// WithCollectFailFast cancels the remaining values on the first error, the same as WithCollectMaxErrors(1)
+func WithCollectFailFast() CollectOption {
+	return WithCollectMaxErrors(1)
+}
+
+// WithCollectMaxErrors cancels the remaining values once the given number of errors have occurred, values less
+// than 1 never cancel
+func WithCollectMaxErrors(maxErrors int) CollectOption {
+	return func(c *collectConfig) {
+		c.maxErrors = maxErrors
+	}
+}
+
+// WithCollectIgnoreErrors discards errors for which the predicate returns true, they are not returned and do not
+// count towards WithCollectMaxErrors or WithCollectFailFast
+func WithCollectIgnoreErrors(predicate func(error) bool) CollectOption {
+	return func(c *collectConfig) {
+		c.ignore = predicate
+	}
+}
+
+// WithCollectGracePeriod waits up to the given duration for executing processors to complete when the context is
+// canceled, so their results are passed to the accumulator, rather than returning immediately
+func WithCollectGracePeriod(grace time.Duration) CollectOption {
+	return func(c *collectConfig) {
+		c.grace = grace
+	}
+}
+
+// WithCollectReport fills the report with which values were processed when CollectWith returns
+func WithCollectReport(report *CollectReport) CollectOption {
+	return func(c *collectConfig) {
+		c.report = report
+	}
+}
+
+// CollectWith is like Collect, configured by the provided options, with the processor receiving a context which is
+// canceled when the remaining values are canceled due to errors, so in-flight processors are able to stop early;
+// context.Cause returns the error which caused the cancellation. Once canceled, no more values are processed, and
+// results from in-flight processors which stopped due to the cancellation are not returned or accumulated. When the
+// context is canceled, CollectWith returns without waiting for executing processors, unless a grace period is
+// configured, and the accumulator is never called after CollectWith returns
+func CollectWith[From, To any](ctx *context.Context, executorName string, iterator iter.Seq[From], processor func(context.Context, From) (To, error), accumulator func(From, To), opts ...CollectOption) error {
+	if processor == nil {
+		panic("no processor provided to CollectWith")
+	}
 	if ctx == nil || *ctx == nil {
 		ctx = emptyContextPtr
 	}
+	var cfg collectConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
 	var errs []error
-	var lock sync.Mutex
+	var failures int
+	// closed is set when CollectWith returns, after which results are discarded
+	var closed bool
+	// processed is only tracked when a report is requested
+	var processed map[int]struct{}
+	if cfg.report != nil {
+		processed = map[int]struct{}{}
+	}
+	// Locking rather than sync.Mutex so the accumulator is a yield point when fuzzing with NewFuzzExecutor
+	var lock Locking
 	var wg sync.WaitGroup
 	executor := ContextExecutor(ctx, executorName)
-	for i := range iterator {
+	runCtx, cancel := context.WithCancelCause(*ctx)
+	defer cancel(nil)
+
+	// record records the error as an ItemError, canceling the remaining values when too many have occurred, must
+	// be called with the lock held. Returns false when the processor stopped due to the cancellation
+	record := func(index int, value From, err error) bool {
+		if err == nil || (cfg.ignore != nil && cfg.ignore(err)) {
+			return true
+		}
+		if cause := context.Cause(runCtx); cause != nil && (*ctx).Err() == nil &&
+			(errors.Is(err, context.Canceled) || errors.Is(err, cause)) {
+			return false
+		}
+		errs = append(errs, ItemError[From]{Index: index, Value: value, Err: err})
+		failures++
+		if cfg.maxErrors > 0 && failures >= cfg.maxErrors {
+			cancel(err)
+		}
+		return true
+	}
+
+	index := 0
+	for value := range iterator {
+		// skip queuing any more values
+		if runCtx.Err() != nil {
+			break
+		}
+		i := index
+		index++
+		wg.Add(1)
+		goSkip(executor, func() {
+			defer wg.Done()
+			defer func() {
+				if err := recover(); err != nil {
+					defer lock.Lock()()
+					if !closed {
+						if processed != nil {
+							processed[i] = struct{}{}
+						}
+						record(i, value, PanicError{Value: err, Stack: string(debug.Stack())})
+					}
+				}
+			}()
+			// we may have queued many functions when canceled
+			if runCtx.Err() != nil {
+				return
+			}
+			result, err := processor(runCtx, value)
+			defer lock.Lock()()
+			if closed || !record(i, value, err) {
+				return
+			}
+			if processed != nil {
+				processed[i] = struct{}{}
+			}
+			if accumulator != nil {
+				accumulator(value, result)
+			}
+		}, func(err error) {
+			defer wg.Done()
+			defer lock.Lock()()
+			// values skipped after canceling are unprocessed rather than failed
+			if !closed && runCtx.Err() == nil {
+				record(i, value, err)
+			}
+		})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-(*ctx).Done():
+		if cfg.grace > 0 {
+			timer := time.NewTimer(cfg.grace)
+			select {
+			case <-done:
+			case <-timer.C:
+			}
+			timer.Stop()
+		}
+	case <-done:
+	}
+
+	defer lock.Lock()()
+	closed = true
+	if cfg.report != nil {
+		*cfg.report = CollectReport{Iterated: index}
+		for i := range index {
+			if _, ok := processed[i]; !ok {
+				cfg.report.Unprocessed = append(cfg.report.Unprocessed, i)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// collectBufferSize is the maximum number of results CollectOrdered holds waiting for an earlier result, and the
+// maximum number of values CollectSeq processes ahead of the consumer, after which no more values are processed
+// until results are used
+const collectBufferSize = 1000
+
+// orderedResult is a processed value waiting to be passed to the accumulator in iteration order
+type orderedResult[From, To any] struct {
+	from From
+	to   To
+	err  error
+	// processed is false when the value was not processed due to cancellation or panicking
+	processed bool
+}
+
+// CollectOrdered is like Collect, executing the processor in parallel but calling the accumulator in the order
+// values are iterated, so results are the same as when processing serially. Errors are joined in iteration order,
+// each as an ItemError identifying the value. Results which complete before an earlier result are buffered; when an
+// earlier value is slow to process, at most a fixed number of values are processed ahead of it. The accumulator is
+// never called after CollectOrdered returns
+func CollectOrdered[From, To any](ctx *context.Context, executorName string, iterator iter.Seq[From], processor func(From) (To, error), accumulator func(From, To)) error {
+	if processor == nil {
+		panic("no processor provided to CollectOrdered")
+	}
+	if ctx == nil || *ctx == nil {
+		ctx = emptyContextPtr
+	}
+	var errs []error
+	// closed is set when CollectOrdered returns, after which results are discarded
+	var closed bool
+	var lock Locking
+	var wg sync.WaitGroup
+	buffered := map[int]orderedResult[From, To]{}
+	next := 0
+	slots := make(chan struct{}, collectBufferSize)
+
+	// complete buffers the result and passes all available results in order to the accumulator, must be called
+	// with the lock held
+	complete := func(index int, result orderedResult[From, To]) {
+		buffered[index] = result
+		for {
+			r, ok := buffered[next]
+			if !ok {
+				return
+			}
+			delete(buffered, next)
+			i := next
+			next++
+			<-slots
+			if r.err != nil {
+				errs = append(errs, ItemError[From]{Index: i, Value: r.from, Err: r.err})
+			}
+			if r.processed && accumulator != nil {
+				if _, err := call(func() (any, error) {
+					accumulator(r.from, r.to)
+					return nil, nil
+				}); err != nil {
+					errs = append(errs, ItemError[From]{Index: i, Value: r.from, Err: err})
+				}
+			}
+		}
+	}
+
+	executor := ContextExecutor(ctx, executorName)
+	index := 0
+	for value := range iterator {
 		// skip queuing any more values
 		if (*ctx).Err() != nil {
 			break
 		}
+		// wait for space in the reorder buffer
+		select {
+		case slots <- struct{}{}:
+		case <-(*ctx).Done():
+		}
+		if (*ctx).Err() != nil {
+			break
+		}
+		i := index
+		index++
 		wg.Add(1)
-		executor.Go(func() {
+		goSkip(executor, func() {
+			result := orderedResult[From, To]{from: value}
 			defer func() {
-				wg.Done()
 				if err := recover(); err != nil {
-					lock.Lock()
-					defer lock.Unlock()
-					errs = append(errs, PanicError{Value: err, Stack: string(debug.Stack())})
+					result.err = PanicError{Value: err, Stack: string(debug.Stack())}
+				}
+				defer lock.Lock()()
+				if !closed {
+					complete(i, result)
 				}
+				wg.Done()
 			}()
 			// we may have queued many functions when canceled
 			if (*ctx).Err() != nil {
 				return
 			}
-			result, err := processor(i)
-			lock.Lock()
-			defer lock.Unlock()
-			if err != nil {
-				errs = append(errs, err)
+			result.to, result.err = processor(value)
+			result.processed = true
+		}, func(err error) {
+			result := orderedResult[From, To]{from: value}
+			if (*ctx).Err() == nil {
+				result.err = err
 			}
-			if accumulator != nil {
-				accumulator(i, result)
+			defer lock.Lock()()
+			if !closed {
+				complete(i, result)
 			}
+			wg.Done()
 		})
 	}
 
@@ -65,9 +336,121 @@ func Collect[From, To any](ctx *context.Context, executorName string, iterator i
 	case <-done:
 	}
 
+	defer lock.Lock()()
+	closed = true
 	return errors.Join(errs...)
 }
 
+// seqResult is a result yielded by CollectSeq
+type seqResult[To any] struct {
+	value To
+	err   error
+}
+
+// CollectSeq returns an iterator which executes the processor in parallel for each value, yielding each result
+// and error as it completes, in completion order. Values are only processed when the iterator is used, and at
+// most a fixed number of values are processed ahead of the consumer. When the consumer stops iterating, no more
+// values are iterated and those not yet processed are skipped; processors already executing continue, but their
+// results are discarded. The iterator of values has returned when iteration stops. Panics from the processor or
+// the iterator are yielded as a PanicError. When the context is canceled, the context error is yielded as the
+// final error, so a truncated sequence can be distinguished from a complete one
+func CollectSeq[From, To any](ctx *context.Context, executorName string, values iter.Seq[From], processor func(From) (To, error)) iter.Seq2[To, error] {
+	if processor == nil {
+		panic("no processor provided to CollectSeq")
+	}
+	if ctx == nil || *ctx == nil {
+		ctx = emptyContextPtr
+	}
+	return func(yield func(To, error) bool) {
+		// the caller's context is replaced with the child executor, as with Collect, so nested collects do not wait
+		// on the units of work executing them
+		executor := ContextExecutor(ctx, executorName)
+		parent := *ctx
+		runCtx, cancel := context.WithCancel(parent)
+		// closed when the iterator of values has returned
+		iterated := make(chan struct{})
+		defer func() {
+			cancel()
+			<-iterated
+		}()
+
+		results := make(chan seqResult[To])
+		slots := make(chan struct{}, collectBufferSize)
+
+		// send passes a result to the consumer, releasing its slot if the consumer stopped iterating
+		send := func(result seqResult[To]) {
+			select {
+			case results <- result:
+			case <-runCtx.Done():
+				<-slots
+			}
+		}
+
+		go func() {
+			var wg sync.WaitGroup
+			defer func() {
+				if err := recover(); err != nil {
+					// a panic from the iterator is yielded like a result
+					select {
+					case slots <- struct{}{}:
+						send(seqResult[To]{err: PanicError{Value: err, Stack: string(debug.Stack())}})
+					case <-runCtx.Done():
+					}
+				}
+				close(iterated)
+				wg.Wait()
+				close(results)
+			}()
+			for value := range values {
+				// wait until the consumer has used enough results
+				select {
+				case slots <- struct{}{}:
+				case <-runCtx.Done():
+				}
+				if runCtx.Err() != nil {
+					return
+				}
+				wg.Add(1)
+				goSkip(executor, func() {
+					defer wg.Done()
+					if runCtx.Err() != nil {
+						<-slots
+						return
+					}
+					to, err := call(func() (To, error) {
+						return processor(value)
+					})
+					send(seqResult[To]{value: to, err: err})
+				}, func(err error) {
+					defer wg.Done()
+					if runCtx.Err() != nil {
+						<-slots
+						return
+					}
+					send(seqResult[To]{err: err})
+				})
+			}
+		}()
+
+		for {
+			select {
+			case result, ok := <-results:
+				if !ok {
+					return
+				}
+				<-slots
+				if !yield(result.value, result.err) {
+					return
+				}
+			case <-parent.Done():
+				var zero To
+				yield(zero, parent.Err())
+				return
+			}
+		}
+	}
+}
+
 // CollectSlice is a specialized Collect call which appends results to a slice
 func CollectSlice[From, To any](ctx *context.Context, executorName string, values iter.Seq[From], processor func(From) (To, error), slice *[]To) error {
 	return Collect(ctx, executorName, values, processor, func(_ From, value To) {
@@ -82,11 +465,20 @@ func CollectMap[From comparable, To any](ctx *context.Context, executorName stri
 	})
 }
 
-// Collect2 is a specialized Collect call which accepts an iter.Seq2 and maps to processor and accumulator taking 2 input parameters
+// CollectSliceOrdered is a specialized CollectOrdered call which appends results to a slice in the order values
+// are iterated
+func CollectSliceOrdered[From, To any](ctx *context.Context, executorName string, values iter.Seq[From], processor func(From) (To, error), slice *[]To) error {
+	return CollectOrdered(ctx, executorName, values, processor, func(_ From, value To) {
+		*slice = append(*slice, value)
+	})
+}
+
+// Collect2 is a specialized Collect call which accepts an iter.Seq2 and maps to processor and accumulator taking 2 input parameters.
+// Errors are reported as an ItemError[KeyValue[From1, From2]], use ItemErrors[KeyValue[From1, From2]] to get them
 func Collect2[From1, From2, To any](ctx *context.Context, executorName string, iterator iter.Seq2[From1, From2], processor func(From1, From2) (To, error), accumulator func(From1, From2, To)) error {
-	return Collect[keyValue[From1, From2], To](ctx, executorName, toKeyValueIterator(iterator), func(k keyValue[From1, From2]) (To, error) {
+	return Collect[KeyValue[From1, From2], To](ctx, executorName, toKeyValueIterator(iterator), func(k KeyValue[From1, From2]) (To, error) {
 		return processor(k.Key, k.Value)
-	}, func(k keyValue[From1, From2], to To) {
+	}, func(k KeyValue[From1, From2], to To) {
 		accumulator(k.Key, k.Value, to)
 	})
 }
@@ -5,7 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"iter"
+	"maps"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -141,8 +145,7 @@ func Test_CollectCancelRepeat(t *testing.T) {
 
 func Test_CollectCancel(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.TODO())
-	e := &errGroupExecutor{} // use errgroup executor as it will block before executing 3
-	e.g.SetLimit(2)
+	e := newBoundedExecutor(2) // use the bounded executor as it will block before executing 3
 	ctx = SetContextExecutor(ctx, "", e)
 
 	executed3 := false
@@ -171,6 +174,272 @@ func Test_CollectCancel(t *testing.T) {
 	require.False(t, executed3)
 }
 
+func Test_CollectWithFailFast(t *testing.T) {
+	ctx := SetContextExecutor(context.Background(), "", NewExecutor(2))
+	errFail := fmt.Errorf("fail")
+
+	var started atomic.Int32
+	var accumulated []int
+	err := CollectWith(&ctx, "", countIter(100), func(ctx context.Context, i int) (int, error) {
+		started.Add(1)
+		if i == 0 {
+			return 0, errFail
+		}
+		// in-flight processors observe the cancellation
+		select {
+		case <-ctx.Done():
+			return 0, context.Cause(ctx)
+		case <-time.After(5 * time.Second):
+			return i, nil
+		}
+	}, func(i int, _ int) {
+		accumulated = append(accumulated, i)
+	}, WithCollectFailFast())
+
+	// errors caused by the cancellation are not returned
+	require.ErrorIs(t, err, errFail)
+	require.Equal(t, "item 0: fail", err.Error())
+	require.LessOrEqual(t, started.Load(), int32(2))
+	require.LessOrEqual(t, len(accumulated), 2)
+}
+
+func Test_CollectWithMaxErrors(t *testing.T) {
+	ctx := SetContextExecutor(context.Background(), "", NewExecutor(0))
+
+	var processed []int
+	err := CollectWith(&ctx, "", countIter(10), func(ctx context.Context, i int) (int, error) {
+		processed = append(processed, i)
+		if i%2 == 1 {
+			return 0, fmt.Errorf("fail %d", i)
+		}
+		return i, nil
+	}, nil, WithCollectMaxErrors(3))
+
+	require.Equal(t, "item 1: fail 1\nitem 3: fail 3\nitem 5: fail 5", err.Error())
+	require.Equal(t, []int{0, 1, 2, 3, 4, 5}, processed)
+}
+
+func Test_CollectWithIgnoreErrors(t *testing.T) {
+	ctx := SetContextExecutor(context.Background(), "", NewExecutor(0))
+	errIgnored := fmt.Errorf("ignored")
+
+	var accumulated []int
+	err := CollectWith(&ctx, "", countIter(10), func(ctx context.Context, i int) (int, error) {
+		switch {
+		case i == 9:
+			return i, fmt.Errorf("fail %d", i)
+		case i%2 == 1:
+			return i, fmt.Errorf("value %d: %w", i, errIgnored)
+		}
+		return i, nil
+	}, func(i int, _ int) {
+		accumulated = append(accumulated, i)
+	}, WithCollectFailFast(), WithCollectIgnoreErrors(func(err error) bool {
+		return errors.Is(err, errIgnored)
+	}))
+
+	require.Equal(t, "item 9: fail 9", err.Error())
+	require.Len(t, accumulated, 10)
+}
+
+func Test_CollectWithReport(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	e := newBoundedExecutor(2) // blocks submitting 2 until 1 completes
+	ctx = SetContextExecutor(ctx, "", e)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var accumulated []int
+	var report CollectReport
+	err := CollectWith(&ctx, "", countIter(5), func(_ context.Context, i int) (int, error) {
+		switch i {
+		case 0:
+			close(started)
+			<-release
+		case 1:
+			<-started
+			cancel()
+		}
+		return i, nil
+	}, func(i int, _ int) {
+		accumulated = append(accumulated, i)
+	}, WithCollectReport(&report))
+	require.NoError(t, err)
+
+	// 0 was executing when canceled, 2 was skipped and no more values were iterated
+	require.Equal(t, 3, report.Iterated)
+	require.Contains(t, report.Unprocessed, 0)
+	require.Contains(t, report.Unprocessed, 2)
+
+	// values which were not processed are not reported as succeeded
+	succeeded, failed, unprocessed := SplitItems(slices.Collect(countIter(5)), report, err)
+	require.Equal(t, []int{1}, succeeded)
+	require.Empty(t, failed)
+	require.Equal(t, []int{0, 2, 3, 4}, unprocessed)
+
+	// the accumulator is not called after returning
+	close(release)
+	e.Wait(context.Background())
+	require.NotContains(t, accumulated, 0)
+	require.NotContains(t, accumulated, 2)
+}
+
+func Test_CollectWithGracePeriod(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = SetContextExecutor(ctx, "", newBoundedExecutor(2))
+
+	started := make(chan struct{})
+	var accumulated []int
+	var report CollectReport
+	err := CollectWith(&ctx, "", countIter(5), func(ctx context.Context, i int) (int, error) {
+		switch i {
+		case 0:
+			close(started)
+			// in-flight processors complete after the context is canceled
+			<-ctx.Done()
+			time.Sleep(10 * time.Millisecond)
+		case 1:
+			<-started
+			cancel()
+		}
+		return i, nil
+	}, func(i int, _ int) {
+		accumulated = append(accumulated, i)
+	}, WithCollectGracePeriod(5*time.Second), WithCollectReport(&report))
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []int{0, 1}, accumulated)
+	require.Equal(t, CollectReport{Iterated: 3, Unprocessed: []int{2}}, report)
+}
+
+func Test_CollectItemErrors(t *testing.T) {
+	ctx := SetContextExecutor(context.Background(), "", NewExecutor(0))
+	values := []string{"a", "b", "c", "d", "e"}
+
+	errFail := fmt.Errorf("fail")
+	err := Collect(&ctx, "", ToSeq(values), func(value string) (string, error) {
+		switch value {
+		case "b":
+			return "", errFail
+		case "d":
+			panic("d panicked")
+		}
+		return value, nil
+	}, func(string, string) {})
+	require.ErrorIs(t, err, errFail)
+
+	var item ItemError[string]
+	require.ErrorAs(t, err, &item)
+	require.Equal(t, 1, item.Index)
+	require.Equal(t, "b", item.Value)
+
+	failed := ItemErrors[string](err)
+	require.Len(t, failed, 2)
+	require.Equal(t, 3, failed[1].Index)
+	require.Equal(t, "d", failed[1].Value)
+	var p PanicError
+	require.ErrorAs(t, failed[1], &p)
+	require.Equal(t, "d panicked", p.Value)
+
+	// all values were processed
+	succeeded, failed, unprocessed := SplitItems(values, CollectReport{Iterated: len(values)}, err)
+	require.Equal(t, []string{"a", "c", "e"}, succeeded)
+	require.Len(t, failed, 2)
+	require.Empty(t, unprocessed)
+
+	// other errors are not ItemError
+	require.Empty(t, ItemErrors[string](errors.Join(errFail, nil)))
+	require.Empty(t, ItemErrors[int](err))
+}
+
+func Test_CollectItemErrorsOrder(t *testing.T) {
+	errItem := fmt.Errorf("failed")
+	// errors joined in completion order are returned in iteration order
+	err := errors.Join(
+		ItemError[int]{Index: 2, Value: 20, Err: errItem},
+		ItemError[int]{Index: 0, Value: 0, Err: errItem},
+		ItemError[int]{Index: 1, Value: 10, Err: errItem},
+	)
+	failed := ItemErrors[int](err)
+	require.Len(t, failed, 3)
+	for i, f := range failed {
+		require.Equal(t, i, f.Index)
+		require.Equal(t, i*10, f.Value)
+	}
+}
+
+func Test_Collect2ItemErrors(t *testing.T) {
+	ctx := SetContextExecutor(context.Background(), "", NewExecutor(2))
+	values := map[string]int{"a": 1, "b": 2, "c": 3}
+	errItem := fmt.Errorf("failed")
+
+	err := Collect2(&ctx, "", maps.All(values), func(key string, value int) (int, error) {
+		if value%2 == 1 {
+			return 0, errItem
+		}
+		return value, nil
+	}, func(string, int, int) {})
+	require.ErrorIs(t, err, errItem)
+
+	// errors identify both values passed to the processor
+	failed := ItemErrors[KeyValue[string, int]](err)
+	require.Len(t, failed, 2)
+	var keys []string
+	for _, f := range failed {
+		require.Equal(t, values[f.Value.Key], f.Value.Value)
+		keys = append(keys, f.Value.Key)
+	}
+	require.ElementsMatch(t, []string{"a", "c"}, keys)
+}
+
+func Test_CollectClosedExecutor(t *testing.T) {
+	e := NewExecutor(2)
+	require.NoError(t, e.(ShutdownExecutor).Close())
+	processor := func(i int) (int, error) {
+		return i, nil
+	}
+
+	// values rejected by the executor are reported rather than waited on
+	var values []int
+	ctx := SetContextExecutor(context.Background(), "", e)
+	err := CollectSlice(&ctx, "", countIter(3), processor, &values)
+	require.ErrorIs(t, err, ErrExecutorClosed)
+	require.Len(t, ItemErrors[int](err), 3)
+	require.Empty(t, values)
+
+	ctx = SetContextExecutor(context.Background(), "", e)
+	err = CollectSliceOrdered(&ctx, "", countIter(3), processor, &values)
+	require.ErrorIs(t, err, ErrExecutorClosed)
+	require.Empty(t, values)
+
+	ctx = SetContextExecutor(context.Background(), "", e)
+	var errs []error
+	for _, err := range CollectSeq(&ctx, "", countIter(3), processor) {
+		errs = append(errs, err)
+	}
+	require.Len(t, errs, 3)
+	require.ErrorIs(t, errs[0], ErrExecutorClosed)
+}
+
+func Test_CollectRejectDrop(t *testing.T) {
+	ctx := SetContextExecutor(context.Background(), "", NewQueuedExecutor(1, WithQueueCapacity(1), WithRejectionPolicy(RejectDrop)))
+
+	// values dropped by the full queue are reported rather than waited on
+	var values []int
+	err := CollectSlice(&ctx, "", countIter(10), func(i int) (int, error) {
+		time.Sleep(10 * time.Millisecond)
+		return i, nil
+	}, &values)
+	failed := ItemErrors[int](err)
+	require.NotEmpty(t, failed)
+	for _, f := range failed {
+		require.ErrorIs(t, f, ErrQueueFull)
+	}
+	require.Len(t, values, 10-len(failed))
+}
+
 func Test_CollectSlice(t *testing.T) {
 	const count = 1000
 	const maxConcurrency = 5
@@ -196,6 +465,233 @@ func Test_CollectSlice(t *testing.T) {
 	require.LessOrEqual(t, concurrency.Max(), maxConcurrency)
 }
 
+func Test_CollectSliceOrdered(t *testing.T) {
+	const count = 1000
+	const maxConcurrency = 5
+
+	concurrency := stats.Tracked[int]{}
+	processor := func(i int) (int, error) {
+		defer concurrency.Incr()()
+
+		// complete out of order
+		time.Sleep(time.Duration(i%3) * time.Millisecond)
+
+		return i * 10, nil
+	}
+
+	var serial []int
+	ctx := SetContextExecutor(context.Background(), "", NewExecutor(0))
+	require.NoError(t, CollectSliceOrdered(&ctx, "", countIter(count), processor, &serial))
+
+	var values []int
+	ctx = SetContextExecutor(context.Background(), "", NewExecutor(maxConcurrency))
+	require.NoError(t, CollectSliceOrdered(&ctx, "", countIter(count), processor, &values))
+
+	require.Len(t, values, count)
+	require.Equal(t, serial, values)
+	for i := range count {
+		require.Equal(t, i*10, values[i])
+	}
+
+	require.LessOrEqual(t, concurrency.Max(), maxConcurrency)
+}
+
+func Test_CollectOrderedErrors(t *testing.T) {
+	ctx := SetContextExecutor(context.Background(), "", NewExecutor(-1))
+
+	var values []int
+	err := CollectOrdered(&ctx, "", countIter(6), func(i int) (int, error) {
+		// complete in reverse order
+		time.Sleep(time.Duration(6-i) * time.Millisecond)
+		switch i {
+		case 1:
+			return 0, fmt.Errorf("error 1")
+		case 3:
+			panic("panic 3")
+		case 4:
+			return 0, fmt.Errorf("error 4")
+		}
+		return i, nil
+	}, func(_ int, value int) {
+		values = append(values, value)
+	})
+
+	// errors are in order, and values which panicked are not accumulated
+	require.Equal(t, []int{0, 0, 2, 0, 5}, values)
+	var p PanicError
+	require.ErrorAs(t, err, &p)
+	message := err.Error()
+	require.Less(t, strings.Index(message, "error 1"), strings.Index(message, "panic 3"))
+	require.Less(t, strings.Index(message, "panic 3"), strings.Index(message, "error 4"))
+}
+
+func Test_CollectOrderedBuffer(t *testing.T) {
+	ctx := SetContextExecutor(context.Background(), "", NewExecutor(-1))
+
+	// the first value is slow, the rest are processed until the reorder buffer is full
+	processed := atomic.Int32{}
+	var ahead int32
+	var values []int
+	err := CollectSliceOrdered(&ctx, "", countIter(collectBufferSize*2), func(i int) (int, error) {
+		if i == 0 {
+			for processed.Load() < collectBufferSize-1 {
+				time.Sleep(time.Millisecond)
+			}
+			time.Sleep(10 * time.Millisecond)
+			ahead = processed.Load()
+		} else {
+			processed.Add(1)
+		}
+		return i, nil
+	}, &values)
+	require.NoError(t, err)
+
+	require.Equal(t, int32(collectBufferSize-1), ahead)
+	require.Len(t, values, collectBufferSize*2)
+	require.True(t, slices.IsSorted(values))
+}
+
+func Test_CollectSeq(t *testing.T) {
+	ctx := SetContextExecutor(context.Background(), "", NewExecutor(5))
+
+	processed := atomic.Int32{}
+	results := CollectSeq(&ctx, "", countIter(100), func(i int) (int, error) {
+		processed.Add(1)
+		if i%10 == 0 {
+			return 0, fmt.Errorf("error %d", i)
+		}
+		return i * 10, nil
+	})
+	// nothing is processed until iterating
+	time.Sleep(time.Millisecond)
+	require.Zero(t, processed.Load())
+
+	var values []int
+	var errs []error
+	for value, err := range results {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		values = append(values, value)
+	}
+	require.Len(t, errs, 10)
+	require.Len(t, values, 90)
+	slices.Sort(values)
+	require.Equal(t, 10, values[0])
+	require.Equal(t, 990, values[89])
+}
+
+func Test_CollectSeqNested(t *testing.T) {
+	ctx := SetContextExecutor(context.Background(), "", NewExecutor(2))
+
+	// processors collecting nested values use the child executor rather than waiting on the slots they hold
+	var totals []int
+	for total, err := range CollectSeq(&ctx, "", countIter(4), func(i int) (int, error) {
+		// each processor uses its own copy, since nested collects replace the executor in the context
+		ctx := ctx
+		var values []int
+		err := CollectSlice(&ctx, "", countIter(3), func(j int) (int, error) {
+			return i + j, nil
+		}, &values)
+		total := 0
+		for _, v := range values {
+			total += v
+		}
+		return total, err
+	}) {
+		require.NoError(t, err)
+		totals = append(totals, total)
+	}
+	require.ElementsMatch(t, []int{3, 6, 9, 12}, totals)
+}
+
+func Test_CollectSeqBreak(t *testing.T) {
+	ctx := SetContextExecutor(context.Background(), "", NewExecutor(-1))
+
+	iterated := atomic.Int32{}
+	processed := atomic.Int32{}
+	returned := atomic.Bool{}
+	values := func(yield func(int) bool) {
+		defer returned.Store(true)
+		for i := range collectBufferSize * 10 {
+			iterated.Add(1)
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	count := 0
+	for range CollectSeq(&ctx, "", values, func(i int) (int, error) {
+		processed.Add(1)
+		return i, nil
+	}) {
+		count++
+		if count == 1 {
+			// the consumer is slow, so processing stops when enough results are waiting
+			time.Sleep(20 * time.Millisecond)
+			require.LessOrEqual(t, processed.Load(), int32(collectBufferSize+1))
+		}
+		if count == 3 {
+			break
+		}
+	}
+
+	// stops iterating once the consumer breaks
+	require.True(t, returned.Load())
+	require.Less(t, iterated.Load(), int32(collectBufferSize*10))
+}
+
+func Test_CollectSeqPanics(t *testing.T) {
+	ctx := context.Background()
+
+	values := func(yield func(int) bool) {
+		yield(1)
+		yield(2)
+		panic("iterator panicked")
+	}
+	var errs []error
+	for _, err := range CollectSeq(&ctx, "", values, func(i int) (int, error) {
+		if i == 2 {
+			panic("processor panicked")
+		}
+		return i, nil
+	}) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	require.Len(t, errs, 2)
+	require.ErrorContains(t, errors.Join(errs...), "iterator panicked")
+	require.ErrorContains(t, errors.Join(errs...), "processor panicked")
+}
+
+func Test_CollectSeqCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = SetContextExecutor(ctx, "", NewExecutor(2))
+
+	count := 0
+	var errs []error
+	for _, err := range CollectSeq(&ctx, "", countIter(1000), func(i int) (int, error) {
+		time.Sleep(time.Millisecond)
+		return i, nil
+	}) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		count++
+		if count == 5 {
+			cancel()
+		}
+	}
+	require.Less(t, count, 1000)
+	// the cancellation is reported, so the results are known to be incomplete
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], context.Canceled)
+}
+
 func Test_CollectMap(t *testing.T) {
 	const count = 1000
 	const maxConcurrency = 5
@@ -10,6 +10,12 @@ type executorKey struct {
 	name string
 }
 
+// executorRegistryKey holds the executorRegistry in a context
+type executorRegistryKey struct{}
+
+// executorRegistry is the set of executors registered with SetContextExecutor, by name
+type executorRegistry map[string]Executor
+
 // HasContextExecutor returns true when the named executor is available in the context
 func HasContextExecutor(ctx context.Context, name string) bool {
 	return ctx.Value(executorKey{name: name}) != nil
@@ -19,23 +25,47 @@ func HasContextExecutor(ctx context.Context, name string) bool {
 // and replaces the context with one that contains a new executor which won't deadlock
 func ContextExecutor(ctx *context.Context, name string) Executor {
 	if ctx == nil || *ctx == nil {
-		return serialExecutor{}
+		return &serialExecutor{}
 	}
 	executor, ok := (*ctx).Value(executorKey{name: name}).(Executor)
 	if !ok || executor == nil {
 		if name != ExecutorDefault {
 			return ContextExecutor(ctx, ExecutorDefault)
 		}
-		return serialExecutor{}
+		return &serialExecutor{}
 	}
 	if e, _ := executor.(ChildExecutor); e != nil {
-		*ctx = SetContextExecutor(*ctx, name, e.ChildExecutor())
+		child := e.ChildExecutor()
+		// child executors are labeled with the name of their parent
+		if named, ok := executor.(namedExecutor); ok && child != executor {
+			if parentName := named.executorName(); parentName != "" {
+				nameExecutor(child, parentName)
+			}
+		}
+		*ctx = setContextExecutor(*ctx, name, child)
 	}
 	return executor
 }
 
-// SetContextExecutor returns a context with the named executor for use with GetExecutor
+// SetContextExecutor returns a context with the named executor for use with GetExecutor. Executors created by this
+// package set the LabelExecutor pprof label to the name on the goroutines executing units of work; an executor
+// registered under several names is labeled with the first
 func SetContextExecutor(ctx context.Context, name string, executor Executor) context.Context {
+	nameExecutor(executor, name)
+	// copy the registry so contexts derived from the parent are not affected
+	parent, _ := ctx.Value(executorRegistryKey{}).(executorRegistry)
+	registry := make(executorRegistry, len(parent)+1)
+	for n, e := range parent {
+		registry[n] = e
+	}
+	registry[name] = executor
+	ctx = context.WithValue(ctx, executorRegistryKey{}, registry)
+	return setContextExecutor(ctx, name, executor)
+}
+
+// setContextExecutor returns a context with the named executor without registering it, used to replace an
+// executor with its child
+func setContextExecutor(ctx context.Context, name string, executor Executor) context.Context {
 	return context.WithValue(ctx, executorKey{name: name}, executor)
 }
 
@@ -34,33 +34,33 @@ func Test_defaultExecutor(t *testing.T) {
 		ctx := SetContextExecutor(context.Background(), "cpu", &queuedExecutor{})
 
 		e := ContextExecutor(&ctx, "io")
-		require.IsType(t, serialExecutor{}, e)
+		require.IsType(t, &serialExecutor{}, e)
 	})
 
 	t.Run("no executor", func(t *testing.T) {
 		ctx := context.Background()
 
 		e := ContextExecutor(&ctx, "io")
-		require.IsType(t, serialExecutor{}, e)
+		require.IsType(t, &serialExecutor{}, e)
 	})
 
 	t.Run("no executor get default", func(t *testing.T) {
 		ctx := context.Background()
 
 		e := ContextExecutor(&ctx, ExecutorDefault)
-		require.IsType(t, serialExecutor{}, e)
+		require.IsType(t, &serialExecutor{}, e)
 	})
 
 	t.Run("no context", func(t *testing.T) {
 		e := ContextExecutor(nil, "cpu")
-		require.IsType(t, serialExecutor{}, e)
+		require.IsType(t, &serialExecutor{}, e)
 	})
 
 	t.Run("no context typed nil", func(t *testing.T) {
 		var ctx context.Context
 
 		e := ContextExecutor(&ctx, "cpu")
-		require.IsType(t, serialExecutor{}, e)
+		require.IsType(t, &serialExecutor{}, e)
 	})
 }
 
@@ -94,7 +94,7 @@ func Test_ContextExecutor(t *testing.T) {
 		result := ContextExecutor(&ctx, "cpu")
 
 		require.NotNil(t, result)
-		require.IsType(t, serialExecutor{}, result)
+		require.IsType(t, &serialExecutor{}, result)
 	})
 
 	t.Run("WithDifferentExecutorInContext", func(t *testing.T) {
@@ -103,6 +103,33 @@ func Test_ContextExecutor(t *testing.T) {
 		result := ContextExecutor(&ctx, "cpu")
 
 		require.NotNil(t, result)
-		require.IsType(t, serialExecutor{}, result)
+		require.IsType(t, &serialExecutor{}, result)
 	})
 }
+
+func Test_ContextExecutorStats(t *testing.T) {
+	ctx := SetContextExecutor(context.Background(), "cpu", NewExecutor(2))
+	ctx = SetContextExecutor(ctx, "io", NewQueuedExecutor(2))
+	other := SetContextExecutor(ctx, "other", NewExecutor(0))
+
+	cpu := ContextExecutor(&ctx, "cpu")
+	for range 3 {
+		cpu.Go(func() {})
+	}
+	cpu.Wait(context.Background())
+
+	io := ContextExecutor(&ctx, "io")
+	io.Go(func() {})
+	io.Wait(context.Background())
+
+	// nested units of work are submitted to child executors, which are not included
+	ContextExecutor(&ctx, "cpu").Go(func() {})
+
+	s := ContextExecutorStats(ctx)
+	require.Len(t, s, 2)
+	require.Equal(t, int64(3), s["cpu"].Completed)
+	require.Equal(t, int64(1), s["io"].Completed)
+
+	require.Len(t, ContextExecutorStats(other), 3)
+	require.Empty(t, ContextExecutorStats(context.Background()))
+}
@@ -0,0 +1,107 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Cron(t *testing.T) {
+	// a Wednesday
+	start := time.Date(2025, time.January, 1, 10, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		spec     string
+		expected []time.Time
+	}{
+		{
+			spec: "* * * * *",
+			expected: []time.Time{
+				time.Date(2025, time.January, 1, 10, 31, 0, 0, time.UTC),
+				time.Date(2025, time.January, 1, 10, 32, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "*/20 * * * *",
+			expected: []time.Time{
+				time.Date(2025, time.January, 1, 10, 40, 0, 0, time.UTC),
+				time.Date(2025, time.January, 1, 11, 0, 0, 0, time.UTC),
+				time.Date(2025, time.January, 1, 11, 20, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "15 9-10,22 * * *",
+			expected: []time.Time{
+				time.Date(2025, time.January, 1, 22, 15, 0, 0, time.UTC),
+				time.Date(2025, time.January, 2, 9, 15, 0, 0, time.UTC),
+				time.Date(2025, time.January, 2, 10, 15, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "0 0 * * 7",
+			expected: []time.Time{
+				time.Date(2025, time.January, 5, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, time.January, 12, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			// either day of month or day of week matches
+			spec: "0 12 10 * 5",
+			expected: []time.Time{
+				time.Date(2025, time.January, 3, 12, 0, 0, 0, time.UTC),
+				time.Date(2025, time.January, 10, 12, 0, 0, 0, time.UTC),
+				time.Date(2025, time.January, 17, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "0 0 29 2 *",
+			expected: []time.Time{
+				time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "@monthly",
+			expected: []time.Time{
+				time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec:     "0 0 30 2 *",
+			expected: []time.Time{{}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			s, err := Cron(test.spec)
+			require.NoError(t, err)
+			next := start
+			for _, expected := range test.expected {
+				next = s.Next(next)
+				require.Equal(t, expected, next)
+			}
+		})
+	}
+}
+
+func Test_CronInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+	} {
+		_, err := Cron(spec)
+		require.Error(t, err, spec)
+	}
+	require.Panics(t, func() {
+		MustCron("invalid")
+	})
+}
@@ -1,6 +1,24 @@
 package sync
 
-import "fmt"
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrQueueFull is reported when a unit of work is rejected because the executor queue is at capacity
+var ErrQueueFull = errors.New("executor queue is full")
+
+// ErrExecutorClosed is reported when a unit of work is submitted to an executor after Shutdown or Close is called
+var ErrExecutorClosed = errors.New("executor is closed")
+
+// ErrSkipped is reported when a unit of work is not executed because a call to Wait observed a canceled context,
+// or the executor was failing fast
+var ErrSkipped = errors.New("unit of work was skipped")
+
+// ErrNoFutures is returned from an Any Future when no futures were provided
+var ErrNoFutures = errors.New("no futures provided")
 
 type PanicError struct {
 	Value any
@@ -19,3 +37,71 @@ func (p PanicError) Unwrap() error {
 }
 
 var _ error = (*PanicError)(nil)
+
+// ItemError is an error from processing a single value passed to Collect, or CollectOrdered, including panics as a
+// PanicError, along with the value and its index in iteration order. Use ItemErrors to get all the ItemError from
+// the joined error returned by Collect
+type ItemError[From any] struct {
+	Index int
+	Value From
+	Err   error
+}
+
+func (e ItemError[From]) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+func (e ItemError[From]) Unwrap() error {
+	return e.Err
+}
+
+var _ error = (*ItemError[any])(nil)
+
+// ItemErrors returns all the ItemError contained in the error, such as one joined with errors.Join, ordered by
+// Index. Collect reports errors as values complete, so the joined error itself is not in iteration order
+func ItemErrors[From any](err error) []ItemError[From] {
+	out := itemErrors[From](err)
+	slices.SortStableFunc(out, func(a, b ItemError[From]) int {
+		return cmp.Compare(a.Index, b.Index)
+	})
+	return out
+}
+
+func itemErrors[From any](err error) []ItemError[From] {
+	var out []ItemError[From]
+	switch e := err.(type) {
+	case nil:
+	case ItemError[From]:
+		out = append(out, e)
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			out = append(out, itemErrors[From](err)...)
+		}
+	case interface{ Unwrap() error }:
+		out = itemErrors[From](e.Unwrap())
+	}
+	return out
+}
+
+// SplitItems splits the values passed to CollectWith into those which succeeded, the ItemError of those which
+// failed, based on the error returned, and those which were not processed, based on the report filled by
+// WithCollectReport, such as when the context was canceled
+func SplitItems[From any](values []From, report CollectReport, err error) (succeeded []From, failed []ItemError[From], unprocessed []From) {
+	failed = ItemErrors[From](err)
+	indexes := map[int]struct{}{}
+	for _, f := range failed {
+		indexes[f.Index] = struct{}{}
+	}
+	skipped := map[int]struct{}{}
+	for _, i := range report.Unprocessed {
+		skipped[i] = struct{}{}
+	}
+	for i, value := range values {
+		if _, ok := skipped[i]; ok || i >= report.Iterated {
+			unprocessed = append(unprocessed, value)
+		} else if _, ok := indexes[i]; !ok {
+			succeeded = append(succeeded, value)
+		}
+	}
+	return succeeded, failed, unprocessed
+}
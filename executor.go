@@ -14,10 +14,57 @@ type Executor interface {
 
 	// Wait blocks and waits for all the executing functions to be completed before returning, or the context is cancelled.
 	// if more functions are added to be executed by this executor after the Wait call, these will also complete before Wait proceeds
-	// If the context is canceled, any queued functions will not be executed
+	// If the context is canceled, any queued functions will not be executed, functions added after Wait returns are
+	// executed normally. Functions which are already executing are waited on until they finish, or for up to the
+	// timeout configured with WithDrainTimeout
 	Wait(context.Context)
 }
 
+// ErrorExecutor is an Executor which also accepts units of work that return an error
+type ErrorExecutor interface {
+	Executor
+
+	// GoErr adds a unit of work to be executed by the executor, like Go. Any error returned or panic raised, which
+	// is captured as a PanicError, is reported by the next call to WaitErr
+	GoErr(func() error)
+
+	// WaitErr is like Wait, additionally returning all errors reported since the previous WaitErr call, joined
+	// with errors.Join
+	WaitErr(context.Context) error
+}
+
+// ContextualExecutor is an Executor which also accepts units of work that observe cancellation
+type ContextualExecutor interface {
+	Executor
+
+	// GoCtx adds a unit of work to be executed by the executor, like Go. The provided context is canceled when
+	// a call to Wait or Shutdown observes a canceled context or when a timeout configured with WithTaskTimeout elapses
+	GoCtx(func(context.Context))
+}
+
+// SkipExecutor is an Executor which reports units of work which will not be executed, so submitters waiting on
+// them are not blocked indefinitely
+type SkipExecutor interface {
+	Executor
+
+	// GoSkip adds a unit of work to be executed by the executor, like Go. If the unit of work will not be executed,
+	// skipped is called with the reason instead: ErrExecutorClosed when the executor is not accepting units of work,
+	// ErrQueueFull when it is rejected by a full queue, or ErrSkipped when it is skipped due to a canceled Wait or
+	// failing fast. skipped may be called before GoSkip returns
+	GoSkip(fn func(), skipped func(error))
+}
+
+// Resizable is implemented by executors which allow changing the maximum concurrency while units of work are
+// executing. When reduced, executing units of work continue and no more are started until the number executing
+// is below the new maximum
+type Resizable interface {
+	// MaxConcurrency returns the current maximum concurrency
+	MaxConcurrency() int
+
+	// SetMaxConcurrency changes the maximum concurrency, values < 1 are treated as 1
+	SetMaxConcurrency(maxConcurrency int)
+}
+
 // ChildExecutor interface, if implemented, will cause ContextExecutor calls to replace the provided context with one
 // containing a child executor returned from this function. This is used when it is not safe to nest Go calls
 type ChildExecutor interface {
@@ -29,12 +76,33 @@ type ChildExecutor interface {
 //	< 0: unbounded, spawn a new goroutine for each Go call
 //	  0: serial, executes in the same thread/routine as the caller of Go
 //	> 0: a bounded executor with the maximum concurrency provided
-func NewExecutor(maxConcurrency int) Executor {
+//
+// All returned executors implement ErrorExecutor, ContextualExecutor, SkipExecutor, ExecutorStats,
+// ShutdownExecutor and ResultExecutor
+func NewExecutor(maxConcurrency int, opts ...Option) Executor {
+	cfg := newExecutorConfig(opts...)
 	if maxConcurrency < 0 || maxConcurrency > math.MaxInt32 {
-		return &unboundedExecutor{}
+		return newUnboundedExecutor(cfg)
 	}
 	if maxConcurrency == 0 {
-		return serialExecutor{}
+		return newSerialExecutor(cfg)
+	}
+	return newBoundedExecutorWithConfig(maxConcurrency, cfg)
+}
+
+// goSkip adds the unit of work with GoSkip if the executor is a SkipExecutor, otherwise with Go, in which case
+// skipped is never called
+func goSkip(executor Executor, fn func(), skipped func(error)) {
+	if e, ok := executor.(SkipExecutor); ok {
+		e.GoSkip(fn, skipped)
+		return
+	}
+	executor.Go(fn)
+}
+
+// skipTask calls skipped with the reason a unit of work will not be executed, if provided
+func skipTask(skipped func(error), err error) {
+	if skipped != nil {
+		skipped(err)
 	}
-	return newErrGroupExecutor(maxConcurrency)
 }
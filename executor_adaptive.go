@@ -0,0 +1,181 @@
+package sync
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/anchore/go-sync/internal/stats"
+)
+
+const (
+	// adaptiveMinSamples is the minimum number of completed units of work used to calculate average latency
+	adaptiveMinSamples = 5
+
+	// adaptiveTolerance is the ratio of the baseline latency above which the limit is reduced
+	adaptiveTolerance = 1.5
+
+	// adaptiveBackoff is the ratio the limit is multiplied by when latency exceeds the tolerance
+	adaptiveBackoff = 0.75
+
+	// adaptiveBaselineDecay moves the baseline towards the observed latency by 1/adaptiveBaselineDecay each sample
+	// window, so the baseline adapts to changes in the kind of work being executed
+	adaptiveBaselineDecay = 10
+)
+
+// AdaptiveExecutor is an Executor which automatically adjusts its concurrency limit
+type AdaptiveExecutor interface {
+	Executor
+
+	// Limit returns the current concurrency limit
+	Limit() int
+
+	// PeakLimit returns the highest concurrency limit used
+	PeakLimit() int
+}
+
+// adaptiveExecutor is a queuedExecutor which adjusts the maximum concurrency using additive increase,
+// multiplicative decrease based on the latency of completed units of work
+type adaptiveExecutor struct {
+	executor       *queuedExecutor
+	minConcurrency int
+	maxConcurrency int
+	limit          stats.Tracked[int]
+	lock           sync.Mutex
+	samples        int
+	total          time.Duration
+	baseline       time.Duration
+}
+
+var _ interface {
+	AdaptiveExecutor
+	ErrorExecutor
+	ContextualExecutor
+	SkipExecutor
+	ChildExecutor
+	ExecutorStats
+	ShutdownExecutor
+	ResultExecutor
+	namedExecutor
+} = (*adaptiveExecutor)(nil)
+
+// NewAdaptiveExecutor returns an executor which queues units of work like NewQueuedExecutor, starting with a
+// concurrency limit of minConcurrency. After each window of completed units of work, when the average latency is
+// within a tolerance of the lowest latency observed and units of work are waiting, the limit is increased by 1,
+// up to maxConcurrency; when the latency exceeds the tolerance, the limit is reduced, down to minConcurrency
+func NewAdaptiveExecutor(minConcurrency, maxConcurrency int, opts ...Option) AdaptiveExecutor {
+	minConcurrency = max(minConcurrency, 1)
+	maxConcurrency = max(maxConcurrency, minConcurrency)
+	e := &adaptiveExecutor{
+		executor:       newQueuedExecutorWithConfig(minConcurrency, newExecutorConfig(opts...)),
+		minConcurrency: minConcurrency,
+		maxConcurrency: maxConcurrency,
+	}
+	e.limit.Add(minConcurrency)
+	e.executor.completed = e.observe
+	return e
+}
+
+func (e *adaptiveExecutor) Go(f func()) {
+	e.executor.Go(f)
+}
+
+func (e *adaptiveExecutor) GoSkip(f func(), skipped func(error)) {
+	e.executor.GoSkip(f, skipped)
+}
+
+func (e *adaptiveExecutor) GoErr(f func() error) {
+	e.executor.GoErr(f)
+}
+
+func (e *adaptiveExecutor) GoCtx(f func(context.Context)) {
+	e.executor.GoCtx(f)
+}
+
+func (e *adaptiveExecutor) executorName() string {
+	return e.executor.executorName()
+}
+
+func (e *adaptiveExecutor) setExecutorName(name string) {
+	e.executor.setExecutorName(name)
+}
+
+func (e *adaptiveExecutor) Wait(ctx context.Context) {
+	e.executor.Wait(ctx)
+}
+
+func (e *adaptiveExecutor) WaitResult(ctx context.Context) WaitResult {
+	return e.executor.WaitResult(ctx)
+}
+
+func (e *adaptiveExecutor) WaitErr(ctx context.Context) error {
+	return e.executor.WaitErr(ctx)
+}
+
+// ChildExecutor returns the child of the underlying queued executor, or this executor with the ChildSelf strategy
+func (e *adaptiveExecutor) ChildExecutor() Executor {
+	child := e.executor.ChildExecutor()
+	if child == e.executor {
+		return e
+	}
+	return child
+}
+
+func (e *adaptiveExecutor) Shutdown(ctx context.Context) error {
+	return e.executor.Shutdown(ctx)
+}
+
+func (e *adaptiveExecutor) Close() error {
+	return e.executor.Close()
+}
+
+func (e *adaptiveExecutor) State() ExecutorState {
+	return e.executor.State()
+}
+
+func (e *adaptiveExecutor) Stats() Stats {
+	return e.executor.Stats()
+}
+
+func (e *adaptiveExecutor) Limit() int {
+	return e.limit.Val()
+}
+
+func (e *adaptiveExecutor) PeakLimit() int {
+	return e.limit.Max()
+}
+
+// observe records the latency of a completed unit of work, adjusting the limit after each window of samples
+func (e *adaptiveExecutor) observe(latency time.Duration) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	current := e.limit.Val()
+	e.samples++
+	e.total += latency
+	if e.samples < max(current, adaptiveMinSamples) {
+		return
+	}
+	avg := e.total / time.Duration(e.samples)
+	e.samples = 0
+	e.total = 0
+
+	if e.baseline == 0 || avg < e.baseline {
+		e.baseline = avg
+	} else {
+		e.baseline += (avg - e.baseline) / adaptiveBaselineDecay
+	}
+
+	next := current
+	switch {
+	case float64(avg) > float64(e.baseline)*adaptiveTolerance:
+		next = max(e.minConcurrency, int(float64(current)*adaptiveBackoff))
+	case e.executor.queued() > 0:
+		// only increase when there is more work waiting than the current limit allows
+		next = min(e.maxConcurrency, current+1)
+	}
+	if next != current {
+		e.limit.Add(next - current)
+		e.executor.SetMaxConcurrency(next)
+	}
+}
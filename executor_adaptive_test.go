@@ -0,0 +1,99 @@
+package sync
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/go-sync/internal/stats"
+)
+
+func Test_AdaptiveExecutorIncreases(t *testing.T) {
+	e := NewAdaptiveExecutor(1, 8)
+	require.Equal(t, 1, e.Limit())
+
+	concurrency := stats.Tracked[int]{}
+	for range 300 {
+		// latency is not affected by concurrency, so the limit should increase
+		e.Go(func() {
+			defer concurrency.Incr()()
+			time.Sleep(time.Millisecond)
+		})
+	}
+	e.Wait(context.Background())
+
+	require.Greater(t, e.PeakLimit(), 4)
+	require.LessOrEqual(t, e.PeakLimit(), 8)
+	require.LessOrEqual(t, concurrency.Max(), e.PeakLimit())
+}
+
+func Test_AdaptiveExecutorDecreases(t *testing.T) {
+	const count = 200
+	clock := NewFakeClock(time.Now())
+	e := NewAdaptiveExecutor(1, 8, WithClock(clock))
+
+	// count the latencies observed, so the clock is only advanced when no unit of work is between its timer
+	// firing and its latency being observed
+	observed := atomic.Int64{}
+	q := e.(*adaptiveExecutor).executor
+	observe := q.completed
+	q.completed = func(latency time.Duration) {
+		observe(latency)
+		observed.Add(1)
+	}
+
+	running := atomic.Int64{}
+	started := atomic.Int64{}
+	for range count {
+		// latency grows quickly with concurrency, so the limit should stay low
+		e.Go(func() {
+			n := running.Add(1)
+			defer running.Add(-1)
+			started.Add(1)
+			<-clock.NewTimer(time.Duration(n*n) * time.Millisecond).C()
+		})
+	}
+
+	for observed.Load() < count {
+		// every started unit of work is either observed or waiting on its timer
+		done := observed.Load()
+		waiting := int64(clock.Timers())
+		if waiting > 0 && done+waiting == started.Load() {
+			clock.Advance(time.Millisecond)
+		} else {
+			runtime.Gosched()
+		}
+	}
+	e.Wait(context.Background())
+
+	require.GreaterOrEqual(t, e.Limit(), 1)
+	require.LessOrEqual(t, e.Limit(), 2)
+	require.Less(t, e.PeakLimit(), 8)
+}
+
+func Test_AdaptiveExecutorChild(t *testing.T) {
+	e := NewAdaptiveExecutor(1, 2, WithChildStrategy(ChildSelf))
+	require.Same(t, e, e.(ChildExecutor).ChildExecutor())
+
+	e = NewAdaptiveExecutor(1, 2)
+	require.NotSame(t, e, e.(ChildExecutor).ChildExecutor())
+}
+
+func Test_AdaptiveExecutorBounds(t *testing.T) {
+	e := NewAdaptiveExecutor(0, -1)
+	require.Equal(t, 1, e.Limit())
+
+	executed := atomic.Int32{}
+	for range 20 {
+		e.Go(func() {
+			executed.Add(1)
+		})
+	}
+	e.Wait(context.Background())
+	require.Equal(t, int32(20), executed.Load())
+	require.Equal(t, 1, e.PeakLimit())
+}
@@ -0,0 +1,166 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/anchore/go-sync/internal/semaphore"
+)
+
+// boundedExecutor is an Executor that executes units of work, blocking when Go is called once the maxConcurrency
+// is reached, only continuing subsequent Go calls when the number of executing functions drops below
+// maxConcurrency. The maxConcurrency may be changed while executing, errors do not cancel other units of work
+type boundedExecutor struct {
+	life          lifecycle
+	sem           *semaphore.Weighted
+	childLock     sync.RWMutex
+	childExecutor *boundedExecutor
+	config        executorConfig
+	errs          taskErrors
+	tasks         taskContexts
+	stats         taskStats
+	taskLabels
+}
+
+func newBoundedExecutor(maxConcurrency int) *boundedExecutor {
+	return newBoundedExecutorWithConfig(maxConcurrency, executorConfig{})
+}
+
+func newBoundedExecutorWithConfig(maxConcurrency int, config executorConfig) *boundedExecutor {
+	return &boundedExecutor{
+		sem:    semaphore.NewWeighted(int64(max(maxConcurrency, 1))),
+		config: config,
+		life:   newLifecycle(config),
+		errs:   newTaskErrors(config),
+		tasks:  newTaskContexts(config),
+		stats:  newTaskStats(config),
+	}
+}
+
+func (e *boundedExecutor) Go(f func()) {
+	e.GoSkip(f, nil)
+}
+
+func (e *boundedExecutor) GoSkip(f func(), skipped func(error)) {
+	group, id, ok := e.life.add(f)
+	if !ok {
+		rejectClosed(&e.config, &e.errs, &e.stats, skipped)
+		return
+	}
+	submitted := e.stats.submitted()
+	// acquiring is abandoned when a call to Wait observes a canceled context
+	if err := e.sem.Acquire(e.tasks.context(), 1); err != nil {
+		e.stats.skipped()
+		group.skip(id)
+		group.wg.Done()
+		skipTask(skipped, ErrSkipped)
+		return
+	}
+	go func() {
+		defer group.wg.Done()
+		defer e.sem.Release(1)
+		if !group.start(id, e.errs.skip()) {
+			e.stats.skipped()
+			skipTask(skipped, ErrSkipped)
+			return
+		}
+		defer group.finish()
+		defer e.stats.started(submitted)()
+		e.errs.call(e.wrap(f))
+	}()
+}
+
+func (e *boundedExecutor) GoErr(f func() error) {
+	e.Go(func() {
+		e.errs.run(f)
+	})
+}
+
+func (e *boundedExecutor) GoCtx(f func(context.Context)) {
+	ctx := e.tasks.context()
+	e.Go(func() {
+		e.tasks.run(ctx, f)
+	})
+}
+
+func (e *boundedExecutor) Wait(ctx context.Context) {
+	e.WaitResult(ctx)
+}
+
+func (e *boundedExecutor) WaitResult(ctx context.Context) WaitResult {
+	result, _ := e.life.wait(ctx, &e.tasks)
+	return result
+}
+
+func (e *boundedExecutor) WaitErr(ctx context.Context) error {
+	e.Wait(ctx)
+	return e.errs.take()
+}
+
+// Shutdown shuts down the executor and then the child executor, if one was created
+func (e *boundedExecutor) Shutdown(ctx context.Context) error {
+	err := errors.Join(e.life.shutdown(ctx, &e.tasks), e.errs.take())
+	e.childLock.RLock()
+	child := e.childExecutor
+	e.childLock.RUnlock()
+	if child != nil {
+		err = errors.Join(err, child.Shutdown(ctx))
+	}
+	return err
+}
+
+func (e *boundedExecutor) Close() error {
+	ctx, cancel := e.life.closeContext()
+	defer cancel()
+	return e.Shutdown(ctx)
+}
+
+func (e *boundedExecutor) State() ExecutorState {
+	return e.life.State()
+}
+
+func (e *boundedExecutor) Stats() Stats {
+	return e.stats.snapshot(&e.errs)
+}
+
+func (e *boundedExecutor) ChildExecutor() Executor {
+	e.childLock.RLock()
+	child := e.childExecutor
+	e.childLock.RUnlock()
+	if child != nil {
+		return child
+	}
+	e.childLock.Lock()
+	defer e.childLock.Unlock()
+	if e.childExecutor == nil {
+		// create child executor with same bound
+		e.childExecutor = newBoundedExecutorWithConfig(e.MaxConcurrency(), e.config)
+	}
+	return e.childExecutor
+}
+
+func (e *boundedExecutor) MaxConcurrency() int {
+	return int(e.sem.Size())
+}
+
+func (e *boundedExecutor) SetMaxConcurrency(maxConcurrency int) {
+	e.sem.Resize(int64(max(maxConcurrency, 1)))
+	e.childLock.RLock()
+	defer e.childLock.RUnlock()
+	if e.childExecutor != nil {
+		e.childExecutor.SetMaxConcurrency(maxConcurrency)
+	}
+}
+
+var _ interface {
+	ErrorExecutor
+	ContextualExecutor
+	SkipExecutor
+	ChildExecutor
+	Resizable
+	ExecutorStats
+	ShutdownExecutor
+	ResultExecutor
+	namedExecutor
+} = (*boundedExecutor)(nil)
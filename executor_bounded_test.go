@@ -8,19 +8,18 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func Test_errGroupExecutorRepeated(t *testing.T) {
+func Test_boundedExecutorRepeated(t *testing.T) {
 	// iterating these tests many times tends to make problems apparent much more quickly,
 	// when they may succeed under certain conditions
 	for range 1000 {
-		Test_errGroupExecutor(t)
+		Test_boundedExecutor(t)
 	}
 }
 
-func Test_errGroupExecutor(t *testing.T) {
+func Test_boundedExecutor(t *testing.T) {
 	// this test sets up specific wait groups to ensure that the maximum concurrency is honored
 	// by stepping through and holding specific locks while conditions are verified
-	e := errGroupExecutor{maxConcurrency: 2}
-	e.g.SetLimit(2)
+	e := newBoundedExecutor(2)
 
 	wg1 := &sync.WaitGroup{}
 	wg1.Add(1)
@@ -54,7 +53,7 @@ func Test_errGroupExecutor(t *testing.T) {
 
 	wgReady.Wait()
 
-	// errgroup execution is blocking, so the next e.Go will block, so continue on the first before we deadlock
+	// bounded execution is blocking, so the next e.Go will block, so continue on the first before we deadlock
 	wg1.Done()
 
 	e.Go(func() {
@@ -73,16 +72,15 @@ func Test_errGroupExecutor(t *testing.T) {
 	)
 }
 
-func Test_errGroupExecutorCancelRepeat(t *testing.T) {
+func Test_boundedExecutorCancelRepeat(t *testing.T) {
 	for range 100 {
-		Test_errGroupExecutorCancel(t)
+		Test_boundedExecutorCancel(t)
 	}
 }
 
-func Test_errGroupExecutorCancel(t *testing.T) {
+func Test_boundedExecutorCancel(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
-	e := &errGroupExecutor{}
-	e.g.SetLimit(2)
+	e := newBoundedExecutor(2)
 
 	wgs := [3]sync.WaitGroup{}
 	wns := [3]sync.WaitGroup{}
@@ -92,6 +90,7 @@ func Test_errGroupExecutorCancel(t *testing.T) {
 	}
 
 	executed := [3]bool{}
+	submitted := make(chan struct{})
 	e.Go(func() {
 		t.Logf("waiting 0")
 		wns[0].Done()
@@ -114,6 +113,10 @@ func Test_errGroupExecutorCancel(t *testing.T) {
 		// 0 and 1 are currently executing, waiting
 		cancel()
 
+		// the canceled Wait waits for 0 and 1 to finish executing
+		wgs[0].Done()
+		wgs[1].Done()
+
 		wns[2].Wait()
 
 		e.Go(func() {
@@ -122,27 +125,31 @@ func Test_errGroupExecutorCancel(t *testing.T) {
 			t.Logf("done 2")
 			executed[2] = true
 		})
-
-		for i := range wgs {
-			wgs[i].Done()
-		}
-
+		wgs[2].Done()
+		close(submitted)
 	}()
 
 	// should be waiting in 0, 1 not executed 2
 	e.Wait(ctx)
 
+	// should have waited for 0 and 1, and not executed 2
+	require.True(t, executed[0])
+	require.True(t, executed[1])
+	require.False(t, executed[2])
+
 	wns[2].Done()
 
-	// should not have executed 2
-	require.False(t, executed[2])
+	// units of work submitted after a canceled Wait are executed
+	<-submitted
+	e.Wait(context.Background())
+	require.True(t, executed[2])
 }
 
-func Test_errGroupExecutorSubcontext(t *testing.T) {
+func Test_boundedExecutorSubcontext(t *testing.T) {
 	wg := sync.WaitGroup{}
 	wg.Add(1)
 	ctx := context.TODO()
-	ctx = SetContextExecutor(ctx, "", newErrGroupExecutor(1))
+	ctx = SetContextExecutor(ctx, "", newBoundedExecutor(1))
 	ContextExecutor(&ctx, "").Go(func() {
 		// context should be replaced with a secondary executor
 		ContextExecutor(&ctx, "").Go(func() {
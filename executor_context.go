@@ -0,0 +1,75 @@
+package sync
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// taskContexts provides the contexts passed to units of work submitted with GoCtx. Contexts are canceled when
+// Wait observes a canceled context, and optionally when a per-task timeout elapses
+type taskContexts struct {
+	timeout time.Duration
+	clock   Clock
+	lock    sync.Mutex
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+}
+
+func newTaskContexts(config executorConfig) taskContexts {
+	return taskContexts{
+		timeout: config.taskTimeout,
+		clock:   config.getClock(),
+	}
+}
+
+// context returns the context to be used by a newly submitted unit of work
+func (t *taskContexts) context() context.Context {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.ctx == nil {
+		t.ctx, t.cancel = context.WithCancelCause(context.Background())
+	}
+	return t.ctx
+}
+
+// cancelAll cancels the contexts of all units of work submitted so far, with the cause of the provided context
+func (t *taskContexts) cancelAll(ctx context.Context) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.cancel != nil {
+		t.cancel(context.Cause(ctx))
+	}
+}
+
+// reset causes subsequently submitted units of work to receive a new context if the current one has been canceled
+func (t *taskContexts) reset() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.ctx != nil && t.ctx.Err() != nil {
+		t.ctx, t.cancel = nil, nil
+	}
+}
+
+// wait should be called by Wait implementations with the provided context before waiting, returning a function
+// to be called when the context is canceled
+func (t *taskContexts) wait(ctx context.Context) (canceled func()) {
+	if ctx.Err() != nil {
+		t.cancelAll(ctx)
+	} else {
+		t.reset()
+	}
+	return func() {
+		t.cancelAll(ctx)
+	}
+}
+
+// run executes the function with the context, applying the per-task timeout if configured, measured by the clock
+func (t *taskContexts) run(ctx context.Context, fn func(context.Context)) {
+	if t.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = withClockTimeout(ctx, t.clock, t.timeout)
+		defer cancel()
+	}
+	fn(ctx)
+}
@@ -0,0 +1,33 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_taskContexts(t *testing.T) {
+	tasks := taskContexts{}
+
+	first := tasks.context()
+	require.Same(t, first, tasks.context())
+
+	errStop := fmt.Errorf("stop")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	canceled := tasks.wait(ctx)
+	require.NoError(t, first.Err())
+
+	cancel(errStop)
+	canceled()
+	require.ErrorIs(t, context.Cause(first), errStop)
+
+	// until the next wait, units of work receive the canceled context
+	require.Same(t, first, tasks.context())
+
+	_ = tasks.wait(context.Background())
+	second := tasks.context()
+	require.NotSame(t, first, second)
+	require.NoError(t, second.Err())
+}
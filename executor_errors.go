@@ -0,0 +1,92 @@
+package sync
+
+import (
+	"errors"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// taskErrors collects errors from units of work submitted with GoErr, optionally failing fast, and handles
+// panics from units of work submitted with Go according to the PanicPolicy
+type taskErrors struct {
+	failFast     bool
+	panicPolicy  PanicPolicy
+	panicHandler func(PanicError)
+	failed       atomic.Bool
+	panics       atomic.Int64
+	errs         List[error]
+}
+
+func newTaskErrors(config executorConfig) taskErrors {
+	return taskErrors{
+		failFast:     config.failFast,
+		panicPolicy:  config.panicPolicy,
+		panicHandler: config.panicHandler,
+	}
+}
+
+// call executes the function, handling any panic according to the PanicPolicy
+func (t *taskErrors) call(fn func()) {
+	defer func() {
+		if v := recover(); v != nil {
+			t.panics.Add(1)
+			t.panicked(PanicError{Value: v, Stack: string(debug.Stack())})
+		}
+	}()
+	fn()
+}
+
+func (t *taskErrors) panicked(p PanicError) {
+	switch t.panicPolicy {
+	case PanicHandle:
+		if t.panicHandler != nil {
+			t.panicHandler(p)
+		}
+	case PanicCapture:
+		t.fail(p)
+	default:
+		panic(p)
+	}
+}
+
+// run executes the function, recording any returned error or panic
+func (t *taskErrors) run(fn func() error) {
+	defer func() {
+		if v := recover(); v != nil {
+			t.panics.Add(1)
+			t.fail(PanicError{Value: v, Stack: string(debug.Stack())})
+		}
+	}()
+	if err := fn(); err != nil {
+		t.fail(err)
+	}
+}
+
+// fail records the error, causing subsequent units of work to be skipped when failing fast
+func (t *taskErrors) fail(err error) {
+	t.errs.Append(err)
+	if t.failFast {
+		t.failed.Store(true)
+	}
+}
+
+// record records the error without failing fast, used for errors not originating from a unit of work
+func (t *taskErrors) record(err error) {
+	t.errs.Append(err)
+}
+
+// skip returns true when units of work not yet started should be skipped due to a previous failure
+func (t *taskErrors) skip() bool {
+	return t.failed.Load()
+}
+
+// take returns all errors recorded since the last call joined with errors.Join, and resets the fail fast state
+func (t *taskErrors) take() error {
+	var errs []error
+	t.errs.Update(func(values []error) []error {
+		errs = values
+		return nil
+	})
+	t.failed.Store(false)
+	return errors.Join(errs...)
+}
@@ -0,0 +1,437 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand/v2"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// fuzzPollMin and fuzzPollMax bound how often goroutine states are checked while a fuzz executor is waiting
+	// for submitting goroutines to block, or for a unit of work which may be blocked outside a yield point
+	fuzzPollMin = 50 * time.Microsecond
+	fuzzPollMax = 5 * time.Millisecond
+)
+
+// fuzzExecutor executes one unit of work at a time, each in its own goroutine, switching between them at yield
+// points in a pseudo-random order determined by the seed
+type fuzzExecutor struct {
+	config        executorConfig
+	life          lifecycle
+	lock          sync.Mutex
+	rand          *rand.Rand
+	runnable      []*fuzzTask
+	running       *fuzzTask
+	submitters    map[int64]struct{}
+	submitted     bool
+	driving       bool
+	wake          chan struct{}
+	childLock     sync.RWMutex
+	childExecutor Executor
+	errs          taskErrors
+	tasks         taskContexts
+	stats         taskStats
+	taskLabels
+}
+
+var _ interface {
+	ErrorExecutor
+	ContextualExecutor
+	SkipExecutor
+	ChildExecutor
+	ExecutorStats
+	ShutdownExecutor
+	ResultExecutor
+	namedExecutor
+} = (*fuzzExecutor)(nil)
+
+// fuzzTask is a unit of work executed by a fuzzExecutor
+type fuzzTask struct {
+	executor *fuzzExecutor
+	fn       func()
+	group    *taskGroup
+	resume   chan struct{}
+	started  bool
+	resumed  bool
+	gid      int64
+	// held is the number of Locking locks held, only accessed by the goroutine executing the unit of work
+	held int
+}
+
+// fuzzTasks holds the fuzzTask being executed by each goroutine, by goroutine id, so yield points can find the
+// unit of work executing them. fuzzActive is the number of units of work executing outside a yield point, so the
+// goroutine id is only looked up while a unit of work may be the caller, rather than whenever a fuzz executor has
+// units of work, since most of the time all of them are waiting to be resumed
+var (
+	fuzzTasks  sync.Map
+	fuzzActive atomic.Int64
+)
+
+// NewFuzzExecutor returns an executor for finding ordering bugs in tests. Units of work execute one at a time, each
+// in its own goroutine, and switch at yield points: before acquiring a Locking lock, which includes List operations,
+// and when a unit of work completes. Which unit of work continues at each yield point is chosen pseudo-randomly
+// based on the seed, so the same seed usually reproduces a failing interleaving. Units of work are not started
+// while a goroutine which called Go is still running; this is detected by periodically inspecting goroutine
+// states, so the units of work available to choose from may depend on timing, and reproduction is best effort
+// rather than guaranteed. Interleavings are more likely to reproduce when units of work synchronize using Locking;
+// a unit of work blocked in another way, such as on a channel, is detected and another is executed in the
+// meantime, which may continue concurrently once unblocked
+func NewFuzzExecutor(seed uint64, opts ...Option) Executor {
+	e := &fuzzExecutor{
+		config:     newExecutorConfig(opts...),
+		rand:       rand.New(rand.NewPCG(seed, seed)),
+		submitters: map[int64]struct{}{},
+		wake:       make(chan struct{}, 1),
+	}
+	e.life = newLifecycle(e.config)
+	e.errs = newTaskErrors(e.config)
+	e.tasks = newTaskContexts(e.config)
+	e.stats = newTaskStats(e.config)
+	e.life.drain = e.drain
+	return e
+}
+
+func (e *fuzzExecutor) Go(f func()) {
+	e.GoSkip(f, nil)
+}
+
+func (e *fuzzExecutor) GoSkip(f func(), skipped func(error)) {
+	group, id, ok := e.life.add(f)
+	if !ok {
+		rejectClosed(&e.config, &e.errs, &e.stats, skipped)
+		return
+	}
+	submitted := e.stats.submitted()
+	fn := func() {
+		defer group.wg.Done()
+		if !group.start(id, e.errs.skip()) {
+			e.stats.skipped()
+			skipTask(skipped, ErrSkipped)
+			return
+		}
+		defer group.finish()
+		defer e.stats.started(submitted)()
+		e.errs.call(e.wrap(f))
+	}
+	task := &fuzzTask{
+		executor: e,
+		fn:       fn,
+		group:    group,
+		resume:   make(chan struct{}, 1),
+	}
+
+	// units of work submitted by executing units of work are submitted in a deterministic order, others are not
+	// chosen from until the submitting goroutine blocks
+	var submitter int64
+	if current := currentFuzzTask(); current == nil || current.executor != e {
+		submitter = goroutineID()
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.runnable = append(e.runnable, task)
+	if submitter != 0 {
+		e.submitters[submitter] = struct{}{}
+		e.submitted = true
+	}
+	e.notify()
+}
+
+func (e *fuzzExecutor) GoErr(f func() error) {
+	e.Go(func() {
+		e.errs.run(f)
+	})
+}
+
+func (e *fuzzExecutor) GoCtx(f func(context.Context)) {
+	ctx := e.tasks.context()
+	e.Go(func() {
+		e.tasks.run(ctx, f)
+	})
+}
+
+func (e *fuzzExecutor) Wait(ctx context.Context) {
+	e.WaitResult(ctx)
+}
+
+// WaitResult waits like Wait, when the context is canceled units of work which have not started are skipped
+func (e *fuzzExecutor) WaitResult(ctx context.Context) WaitResult {
+	result, _ := e.life.wait(ctx, &e.tasks)
+	return result
+}
+
+func (e *fuzzExecutor) WaitErr(ctx context.Context) error {
+	e.Wait(ctx)
+	return e.errs.take()
+}
+
+func (e *fuzzExecutor) Shutdown(ctx context.Context) error {
+	err := errors.Join(e.life.shutdown(ctx, &e.tasks), e.errs.take())
+	return errors.Join(err, shutdownChild(ctx, e, &e.childLock, &e.childExecutor))
+}
+
+func (e *fuzzExecutor) Close() error {
+	ctx, cancel := e.life.closeContext()
+	defer cancel()
+	return e.Shutdown(ctx)
+}
+
+func (e *fuzzExecutor) State() ExecutorState {
+	return e.life.State()
+}
+
+func (e *fuzzExecutor) Stats() Stats {
+	return e.stats.snapshot(&e.errs)
+}
+
+// ChildExecutor returns a serial executor, so nested units of work execute in the goroutine of the unit of work
+// which submitted them and share its yield points
+func (e *fuzzExecutor) ChildExecutor() Executor {
+	e.childLock.RLock()
+	child := e.childExecutor
+	e.childLock.RUnlock()
+	if child != nil {
+		return child
+	}
+	e.childLock.Lock()
+	defer e.childLock.Unlock()
+	if e.childExecutor == nil {
+		e.childExecutor = newSerialExecutor(e.config)
+	}
+	return e.childExecutor
+}
+
+// notify wakes the driving goroutine, starting it if needed, must be called with the lock held
+func (e *fuzzExecutor) notify() {
+	if !e.driving {
+		e.driving = true
+		go e.drive()
+		return
+	}
+	select {
+	case e.wake <- struct{}{}:
+	default:
+	}
+}
+
+// drive chooses the unit of work to execute each time the executing one yields, exiting when there are none
+func (e *fuzzExecutor) drive() {
+	poll := fuzzPollMin
+	for {
+		e.lock.Lock()
+		if e.running == nil && len(e.runnable) == 0 {
+			e.driving = false
+			e.lock.Unlock()
+			return
+		}
+		if e.running == nil && !e.submitted {
+			e.resumeNext()
+			e.lock.Unlock()
+			poll = fuzzPollMin
+			continue
+		}
+		e.lock.Unlock()
+
+		timer := time.NewTimer(poll)
+		select {
+		case <-e.wake:
+			timer.Stop()
+			poll = fuzzPollMin
+			continue
+		case <-timer.C:
+		}
+		poll = min(poll*2, fuzzPollMax)
+		e.checkBlocked()
+	}
+}
+
+// checkBlocked inspects goroutine states, clearing the submitted flag once all submitting goroutines are blocked
+// or have exited, and detaching the executing unit of work if it is blocked outside a yield point
+func (e *fuzzExecutor) checkBlocked() {
+	states := goroutineStates()
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.submitted {
+		idle := true
+		for gid := range e.submitters {
+			state, ok := states[gid]
+			if !ok {
+				delete(e.submitters, gid)
+				continue
+			}
+			if !blockedState(state) {
+				idle = false
+			}
+		}
+		e.submitted = !idle
+	}
+	if t := e.running; t != nil && t.resumed && blockedState(states[t.gid]) {
+		e.running = nil
+	}
+}
+
+// resumeNext chooses the next unit of work to execute, must be called with the lock held
+func (e *fuzzExecutor) resumeNext() {
+	i := e.rand.IntN(len(e.runnable))
+	t := e.runnable[i]
+	e.runnable = append(e.runnable[:i], e.runnable[i+1:]...)
+	e.running = t
+	t.resumed = false
+	if !t.started {
+		t.started = true
+		go e.exec(t)
+		return
+	}
+	t.resume <- struct{}{}
+}
+
+// exec executes the unit of work in the current goroutine
+func (e *fuzzExecutor) exec(t *fuzzTask) {
+	gid := goroutineID()
+	e.lock.Lock()
+	t.gid = gid
+	t.resumed = true
+	e.lock.Unlock()
+
+	fuzzTasks.Store(gid, t)
+	fuzzActive.Add(1)
+	defer func() {
+		fuzzTasks.Delete(gid)
+		fuzzActive.Add(-1)
+
+		e.lock.Lock()
+		defer e.lock.Unlock()
+		if e.running == t {
+			e.running = nil
+		}
+		e.notify()
+	}()
+	t.fn()
+}
+
+// yield allows another unit of work to execute, blocking until this one is chosen again
+func (t *fuzzTask) yield() {
+	e := t.executor
+	e.lock.Lock()
+	if e.running == t {
+		e.running = nil
+	}
+	e.runnable = append(e.runnable, t)
+	e.notify()
+	e.lock.Unlock()
+
+	fuzzActive.Add(-1)
+	<-t.resume
+	fuzzActive.Add(1)
+
+	e.lock.Lock()
+	t.resumed = true
+	e.lock.Unlock()
+}
+
+// acquire calls lock, yielding first unless the unit of work already holds a lock, since yielding while holding a
+// lock would block other units of work which need it
+func (t *fuzzTask) acquire(lock func()) {
+	if t.held == 0 {
+		t.yield()
+	}
+	lock()
+	t.held++
+}
+
+// release records a lock acquired with acquire was released
+func (t *fuzzTask) release() {
+	if t.held > 0 {
+		t.held--
+	}
+}
+
+// drain removes the units of work belonging to canceled groups which have not started, skipping them
+func (e *fuzzExecutor) drain() {
+	e.lock.Lock()
+	var skipped, kept []*fuzzTask
+	for _, t := range e.runnable {
+		if !t.started && t.group.isCanceled() {
+			skipped = append(skipped, t)
+		} else {
+			kept = append(kept, t)
+		}
+	}
+	e.runnable = kept
+	e.lock.Unlock()
+
+	for _, t := range skipped {
+		t.fn()
+	}
+}
+
+// currentFuzzTask returns the unit of work being executed by a fuzz executor in the current goroutine, if any
+func currentFuzzTask() *fuzzTask {
+	if fuzzActive.Load() == 0 {
+		return nil
+	}
+	t, _ := fuzzTasks.Load(goroutineID())
+	task, _ := t.(*fuzzTask)
+	return task
+}
+
+// goroutineID returns the id of the current goroutine, parsed from the header of its stack trace
+func goroutineID() int64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	id, _, _ := strings.Cut(strings.TrimPrefix(string(buf[:n]), "goroutine "), " ")
+	gid, _ := strconv.ParseInt(id, 10, 64)
+	return gid
+}
+
+// goroutineStates returns the wait state of all goroutines by id, parsed from the headers of their stack traces,
+// such as: goroutine 7 [chan receive, 2 minutes]:
+func goroutineStates() map[int64]string {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	states := map[int64]string{}
+	for _, line := range bytes.Split(buf, []byte("\n")) {
+		header, ok := bytes.CutPrefix(line, []byte("goroutine "))
+		if !ok {
+			continue
+		}
+		id, state, ok := bytes.Cut(header, []byte(" ["))
+		if !ok {
+			continue
+		}
+		gid, err := strconv.ParseInt(string(id), 10, 64)
+		if err != nil {
+			continue
+		}
+		state, _, _ = bytes.Cut(state, []byte("]"))
+		state, _, _ = bytes.Cut(state, []byte(","))
+		states[gid] = string(state)
+	}
+	return states
+}
+
+// blockedState returns true when the goroutine state indicates it is waiting on another goroutine or for time to
+// pass, rather than executing
+func blockedState(state string) bool {
+	for _, prefix := range []string{"chan ", "select", "sync.", "semacquire", "sleep", "IO wait"} {
+		if strings.HasPrefix(state, prefix) {
+			return true
+		}
+	}
+	return false
+}
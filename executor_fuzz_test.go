@@ -0,0 +1,116 @@
+package sync
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FuzzExecutor(t *testing.T) {
+	run := func(seed uint64) []int {
+		e := NewFuzzExecutor(seed)
+		order := List[int]{}
+		for i := range 5 {
+			e.Go(func() {
+				for range 3 {
+					order.Append(i)
+				}
+			})
+		}
+		e.Wait(context.Background())
+		return order.Values()
+	}
+
+	orders := map[string]struct{}{}
+	for seed := range uint64(20) {
+		order := run(seed)
+		require.Len(t, order, 15)
+		// the same seed reproduces the same interleaving
+		require.Equal(t, order, run(seed))
+		orders[string(toBytes(order))] = struct{}{}
+	}
+	require.Greater(t, len(orders), 1)
+}
+
+func Test_FuzzExecutorFindsRace(t *testing.T) {
+	// a check-then-act race: both units of work may observe the value missing before either appends it
+	run := func(seed uint64) []string {
+		e := NewFuzzExecutor(seed)
+		values := List[string]{}
+		for range 2 {
+			e.Go(func() {
+				if !values.Contains("value") {
+					values.Append("value")
+				}
+			})
+		}
+		e.Wait(context.Background())
+		return values.Values()
+	}
+
+	var failing []uint64
+	for seed := range uint64(50) {
+		if len(run(seed)) > 1 {
+			failing = append(failing, seed)
+		}
+	}
+	require.NotEmpty(t, failing)
+	for _, seed := range failing {
+		require.Len(t, run(seed), 2)
+	}
+}
+
+func Test_FuzzExecutorCollect(t *testing.T) {
+	run := func(seed uint64) []int {
+		ctx := SetContextExecutor(context.Background(), "", NewFuzzExecutor(seed))
+		var results []int
+		err := CollectSlice(&ctx, "", slices.Values([]int{1, 2, 3, 4, 5, 6}), func(i int) (int, error) {
+			return i * 10, nil
+		}, &results)
+		require.NoError(t, err)
+		return results
+	}
+
+	orders := map[string]struct{}{}
+	for seed := range uint64(20) {
+		results := run(seed)
+		require.ElementsMatch(t, []int{10, 20, 30, 40, 50, 60}, results)
+		require.Equal(t, results, run(seed))
+		orders[string(toBytes(results))] = struct{}{}
+	}
+	require.Greater(t, len(orders), 1)
+}
+
+func Test_FuzzExecutorBlocked(t *testing.T) {
+	for seed := range uint64(5) {
+		e := NewFuzzExecutor(seed, WithPanicPolicy(PanicCapture)).(ErrorExecutor)
+
+		// units of work blocked outside a yield point do not prevent others from executing
+		ready := make(chan struct{})
+		executed := List[string]{}
+		e.Go(func() {
+			<-ready
+			executed.Append("waiting")
+		})
+		e.Go(func() {
+			executed.Append("ready")
+			close(ready)
+		})
+		e.GoErr(func() error {
+			panic("panicked")
+		})
+		var p PanicError
+		require.ErrorAs(t, e.WaitErr(context.Background()), &p)
+		require.ElementsMatch(t, []string{"ready", "waiting"}, executed.Values())
+	}
+}
+
+func toBytes(values []int) []byte {
+	out := make([]byte, len(values))
+	for i, v := range values {
+		out[i] = byte(v)
+	}
+	return out
+}
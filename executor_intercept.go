@@ -0,0 +1,418 @@
+package sync
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TaskEventKind identifies an event in the life of a unit of work submitted to an executor returned by WrapExecutor
+type TaskEventKind int
+
+const (
+	// TaskSubmitted occurs when a unit of work is passed to Go, GoErr or GoCtx
+	TaskSubmitted TaskEventKind = iota
+
+	// TaskStarted occurs when a unit of work starts executing, in the goroutine executing it
+	TaskStarted
+
+	// TaskFinished occurs when a unit of work returns, in the goroutine executing it
+	TaskFinished
+
+	// TaskPanicked occurs when a unit of work panics, in place of TaskFinished
+	TaskPanicked
+
+	// TaskSkipped occurs when a call to Wait or Shutdown returns and a unit of work submitted before it was called
+	// has not started, such as when the context was canceled or the executor was failing fast, and it will not be
+	// executed. It also occurs when the underlying executor reports it will not execute the unit of work, such as
+	// when it has been closed or its queue is full
+	TaskSkipped
+)
+
+func (k TaskEventKind) String() string {
+	switch k {
+	case TaskSubmitted:
+		return "submitted"
+	case TaskStarted:
+		return "started"
+	case TaskFinished:
+		return "finished"
+	case TaskPanicked:
+		return "panicked"
+	case TaskSkipped:
+		return "skipped"
+	}
+	return "unknown"
+}
+
+// TaskEvent describes an event in the life of a unit of work
+type TaskEvent struct {
+	// Kind is the kind of event
+	Kind TaskEventKind
+
+	// ID identifies the unit of work, it is the same for all events of a unit of work
+	ID uint64
+
+	// Time is when the event occurred
+	Time time.Time
+
+	// WaitTime is the time between the unit of work being submitted and starting, set for started, finished and
+	// panicked events
+	WaitTime time.Duration
+
+	// RunTime is the time the unit of work spent executing, set for finished and panicked events
+	RunTime time.Duration
+
+	// Err is the error returned by a unit of work submitted with GoErr for finished events, or the PanicError for
+	// panicked events
+	Err error
+}
+
+// Interceptor observes the units of work submitted to an executor returned by WrapExecutor, such as to log, trace
+// or record metrics
+type Interceptor interface {
+	// Event is called for each event in the life of a unit of work, it must not block
+	Event(event TaskEvent)
+}
+
+// InterceptorFunc is a function implementing Interceptor
+type InterceptorFunc func(event TaskEvent)
+
+func (f InterceptorFunc) Event(event TaskEvent) {
+	f(event)
+}
+
+// TaskDecorator is an Interceptor which also decorates the functions executed, such as to start a tracing span
+// around each unit of work
+type TaskDecorator interface {
+	Interceptor
+
+	// Decorate returns a function to execute in place of fn, which must call fn. It is called with the started
+	// event in the goroutine executing the unit of work, after the started event is delivered
+	Decorate(started TaskEvent, fn func()) func()
+}
+
+// interceptedExecutor is an Executor which reports the events of each unit of work to interceptors before passing
+// it to another executor
+type interceptedExecutor struct {
+	config        executorConfig
+	clock         Clock
+	executor      Executor
+	interceptors  []Interceptor
+	ids           atomic.Uint64
+	lock          sync.Mutex
+	registered    uint64
+	pending       map[*interceptedTask]struct{}
+	life          lifecycle
+	errs          taskErrors
+	tasks         taskContexts
+	childLock     sync.RWMutex
+	childExecutor Executor
+	taskLabels
+}
+
+var _ interface {
+	ErrorExecutor
+	ContextualExecutor
+	SkipExecutor
+	ChildExecutor
+	ExecutorStats
+	ShutdownExecutor
+	ResultExecutor
+	namedExecutor
+} = (*interceptedExecutor)(nil)
+
+const (
+	interceptedPending int32 = iota
+	interceptedStarted
+	interceptedSkipped
+)
+
+// interceptedTask tracks a unit of work submitted to an interceptedExecutor
+type interceptedTask struct {
+	id        uint64
+	submitted time.Time
+	state     atomic.Int32
+	// skipped is called when the unit of work will not be executed, if it was submitted with GoSkip
+	skipped func(error)
+	// seq orders units of work by when they were accepted by the underlying executor, so units of work accepted
+	// after a call to Wait began are not reported as skipped when it returns
+	seq uint64
+}
+
+// WrapExecutor returns an Executor which reports the events of each unit of work to the interceptors, in the order
+// provided, before passing it to the executor. Decorations from TaskDecorator interceptors are applied so the first
+// is outermost. Panics are reported and then raised again to be handled by the executor, except for units of work
+// submitted with GoErr, which are reported by WaitErr along with errors reported by the executor. Event times are
+// from the Clock set with WithClock
+func WrapExecutor(executor Executor, interceptors []Interceptor, opts ...Option) Executor {
+	return newInterceptedExecutorWithConfig(executor, interceptors, newExecutorConfig(opts...))
+}
+
+func newInterceptedExecutorWithConfig(executor Executor, interceptors []Interceptor, cfg executorConfig) *interceptedExecutor {
+	return &interceptedExecutor{
+		config:       cfg,
+		clock:        cfg.getClock(),
+		executor:     executor,
+		interceptors: interceptors,
+		pending:      map[*interceptedTask]struct{}{},
+		life:         newLifecycle(cfg),
+		errs:         newTaskErrors(cfg),
+		tasks:        newTaskContexts(cfg),
+	}
+}
+
+func (e *interceptedExecutor) Go(f func()) {
+	e.GoSkip(f, nil)
+}
+
+// GoSkip adds the unit of work like Go, calling skipped if it will not be executed, either because the underlying
+// executor reports it was not executed or it was reported as skipped when a call to Wait returned
+func (e *interceptedExecutor) GoSkip(f func(), skipped func(error)) {
+	e.submit(func() error {
+		f()
+		return nil
+	}, false, skipped)
+}
+
+func (e *interceptedExecutor) GoErr(f func() error) {
+	e.submit(f, true, nil)
+}
+
+func (e *interceptedExecutor) GoCtx(f func(context.Context)) {
+	ctx := e.tasks.context()
+	e.Go(func() {
+		e.tasks.run(ctx, f)
+	})
+}
+
+func (e *interceptedExecutor) Wait(ctx context.Context) {
+	e.wait(ctx, func() {
+		e.executor.Wait(ctx)
+	})
+}
+
+// WaitResult waits like Wait, returning the result from the underlying executor if it is a ResultExecutor
+func (e *interceptedExecutor) WaitResult(ctx context.Context) WaitResult {
+	var result WaitResult
+	e.wait(ctx, func() {
+		if executor, ok := e.executor.(ResultExecutor); ok {
+			result = executor.WaitResult(ctx)
+		} else {
+			e.executor.Wait(ctx)
+		}
+	})
+	return result
+}
+
+// WaitErr waits like Wait, returning errors from units of work submitted with GoErr along with the errors
+// reported by the underlying executor, if it is an ErrorExecutor
+func (e *interceptedExecutor) WaitErr(ctx context.Context) error {
+	var err error
+	e.wait(ctx, func() {
+		if executor, ok := e.executor.(ErrorExecutor); ok {
+			err = executor.WaitErr(ctx)
+		} else {
+			e.executor.Wait(ctx)
+		}
+	})
+	return errors.Join(err, e.errs.take())
+}
+
+// Shutdown shuts down the underlying executor, or waits for it if it is not a ShutdownExecutor
+func (e *interceptedExecutor) Shutdown(ctx context.Context) error {
+	e.life.setState(ExecutorDraining)
+	defer e.life.setState(ExecutorClosed)
+	var err error
+	e.wait(ctx, func() {
+		if executor, ok := e.executor.(ShutdownExecutor); ok {
+			err = executor.Shutdown(ctx)
+		} else {
+			e.executor.Wait(ctx)
+			err = ctx.Err()
+		}
+	})
+	return errors.Join(err, e.errs.take())
+}
+
+func (e *interceptedExecutor) Close() error {
+	ctx, cancel := e.life.closeContext()
+	defer cancel()
+	return e.Shutdown(ctx)
+}
+
+func (e *interceptedExecutor) State() ExecutorState {
+	if executor, ok := e.executor.(ShutdownExecutor); ok {
+		return max(e.life.State(), executor.State())
+	}
+	return e.life.State()
+}
+
+// Stats returns the Stats of the underlying executor, if available, including panics from units of work
+// submitted with GoErr
+func (e *interceptedExecutor) Stats() Stats {
+	var s Stats
+	if executor, ok := e.executor.(ExecutorStats); ok {
+		s = executor.Stats()
+	}
+	s.Panicked += e.errs.panics.Load()
+	return s
+}
+
+// ChildExecutor returns the child of the underlying executor wrapped with the same interceptors
+func (e *interceptedExecutor) ChildExecutor() Executor {
+	parent, ok := e.executor.(ChildExecutor)
+	if !ok {
+		return e
+	}
+	e.childLock.RLock()
+	child := e.childExecutor
+	e.childLock.RUnlock()
+	if child != nil {
+		return child
+	}
+	e.childLock.Lock()
+	defer e.childLock.Unlock()
+	if e.childExecutor == nil {
+		if inner := parent.ChildExecutor(); inner == e.executor {
+			e.childExecutor = e
+		} else {
+			e.childExecutor = newInterceptedExecutorWithConfig(inner, e.interceptors, e.config)
+		}
+	}
+	return e.childExecutor
+}
+
+// submit reports the unit of work as submitted and passes it to the underlying executor. When capture is true,
+// errors and panics are recorded to be returned from WaitErr, otherwise panics are raised again
+func (e *interceptedExecutor) submit(fn func() error, capture bool, skipped func(error)) {
+	task := &interceptedTask{
+		id:        e.ids.Add(1),
+		submitted: e.clock.Now(),
+		skipped:   skipped,
+	}
+	e.event(TaskEvent{Kind: TaskSubmitted, ID: task.id, Time: task.submitted})
+
+	goSkip(e.executor, e.wrap(func() {
+		e.execute(task, fn, capture)
+	}), func(err error) {
+		// the unit of work may already have been reported as skipped by a call to Wait
+		if !task.state.CompareAndSwap(interceptedPending, interceptedSkipped) {
+			return
+		}
+		e.lock.Lock()
+		delete(e.pending, task)
+		e.lock.Unlock()
+		e.event(TaskEvent{Kind: TaskSkipped, ID: task.id, Time: e.clock.Now()})
+		skipTask(task.skipped, err)
+	})
+
+	// units of work the executor did not accept have already been reported as skipped, so are not pending
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if task.state.Load() == interceptedPending {
+		e.registered++
+		task.seq = e.registered
+		e.pending[task] = struct{}{}
+	}
+}
+
+// execute executes the unit of work, unless it has been reported as skipped or is skipped because the executor is
+// failing fast
+func (e *interceptedExecutor) execute(task *interceptedTask, fn func() error, capture bool) {
+	state := interceptedStarted
+	if e.errs.skip() {
+		state = interceptedSkipped
+	}
+	if !task.state.CompareAndSwap(interceptedPending, state) {
+		return
+	}
+	e.lock.Lock()
+	delete(e.pending, task)
+	e.lock.Unlock()
+
+	if state == interceptedSkipped {
+		e.event(TaskEvent{Kind: TaskSkipped, ID: task.id, Time: e.clock.Now()})
+		skipTask(task.skipped, ErrSkipped)
+		return
+	}
+
+	start := e.clock.Now()
+	started := TaskEvent{Kind: TaskStarted, ID: task.id, Time: start, WaitTime: start.Sub(task.submitted)}
+	e.event(started)
+
+	var err error
+	run := func() {
+		err = fn()
+	}
+	for i := len(e.interceptors) - 1; i >= 0; i-- {
+		if d, ok := e.interceptors[i].(TaskDecorator); ok {
+			run = d.Decorate(started, run)
+		}
+	}
+
+	defer func() {
+		if v := recover(); v != nil {
+			p := PanicError{Value: v, Stack: string(debug.Stack())}
+			end := e.clock.Now()
+			e.event(TaskEvent{Kind: TaskPanicked, ID: task.id, Time: end, WaitTime: started.WaitTime, RunTime: end.Sub(start), Err: p})
+			if !capture {
+				panic(v)
+			}
+			e.errs.panics.Add(1)
+			e.errs.fail(p)
+		}
+	}()
+	run()
+	end := e.clock.Now()
+	e.event(TaskEvent{Kind: TaskFinished, ID: task.id, Time: end, WaitTime: started.WaitTime, RunTime: end.Sub(start), Err: err})
+	if capture && err != nil {
+		e.errs.fail(err)
+	}
+}
+
+// wait calls the waiting function, then reports units of work accepted before it was called which have not
+// started as skipped, so they will not be executed
+func (e *interceptedExecutor) wait(ctx context.Context, wait func()) {
+	e.lock.Lock()
+	registered := e.registered
+	e.lock.Unlock()
+
+	canceled := e.tasks.wait(ctx)
+	wait()
+	if ctx.Err() != nil {
+		canceled()
+		e.tasks.reset()
+	}
+
+	var skipped []*interceptedTask
+	e.lock.Lock()
+	for task := range e.pending {
+		if task.seq <= registered && task.state.CompareAndSwap(interceptedPending, interceptedSkipped) {
+			delete(e.pending, task)
+			skipped = append(skipped, task)
+		}
+	}
+	e.lock.Unlock()
+
+	slices.SortFunc(skipped, func(a, b *interceptedTask) int {
+		return cmp.Compare(a.id, b.id)
+	})
+	now := e.clock.Now()
+	for _, task := range skipped {
+		e.event(TaskEvent{Kind: TaskSkipped, ID: task.id, Time: now})
+		skipTask(task.skipped, ErrSkipped)
+	}
+}
+
+// event delivers the event to all interceptors
+func (e *interceptedExecutor) event(event TaskEvent) {
+	for _, interceptor := range e.interceptors {
+		interceptor.Event(event)
+	}
+}
@@ -0,0 +1,183 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordingInterceptor records events and decorates units of work by recording when they start and finish
+type recordingInterceptor struct {
+	name   string
+	events List[string]
+	trace  *List[string]
+}
+
+func (r *recordingInterceptor) Event(event TaskEvent) {
+	r.events.Append(fmt.Sprintf("%d %s", event.ID, event.Kind))
+}
+
+func (r *recordingInterceptor) Decorate(_ TaskEvent, fn func()) func() {
+	return func() {
+		r.trace.Append(r.name + " start")
+		defer r.trace.Append(r.name + " end")
+		fn()
+	}
+}
+
+func Test_WrapExecutor(t *testing.T) {
+	trace := List[string]{}
+	outer := &recordingInterceptor{name: "outer", trace: &trace}
+	inner := &recordingInterceptor{name: "inner", trace: &trace}
+
+	var events []TaskEvent
+	clock := NewFakeClock(time.Now())
+	e := WrapExecutor(NewExecutor(0), []Interceptor{outer, inner, InterceptorFunc(func(event TaskEvent) {
+		events = append(events, event)
+	})}, WithClock(clock)).(ErrorExecutor)
+
+	e.Go(func() {
+		trace.Append("executed")
+		clock.Advance(time.Millisecond)
+	})
+	e.GoErr(func() error {
+		return fmt.Errorf("failed")
+	})
+	e.GoErr(func() error {
+		panic("panicked")
+	})
+	err := e.WaitErr(context.Background())
+	require.ErrorContains(t, err, "failed")
+	var p PanicError
+	require.ErrorAs(t, err, &p)
+
+	expected := []string{
+		"1 submitted", "1 started", "1 finished",
+		"2 submitted", "2 started", "2 finished",
+		"3 submitted", "3 started", "3 panicked",
+	}
+	require.Equal(t, expected, outer.events.Values())
+	require.Equal(t, expected, inner.events.Values())
+	require.Equal(t, []string{"outer start", "inner start", "executed", "inner end", "outer end"}, trace.Values()[:5])
+
+	require.Len(t, events, 9)
+	require.Equal(t, time.Millisecond, events[2].RunTime)
+	require.Equal(t, clock.Now(), events[2].Time)
+	require.Equal(t, events[1].WaitTime, events[2].WaitTime)
+	require.NoError(t, events[2].Err)
+	require.ErrorContains(t, events[5].Err, "failed")
+	require.ErrorAs(t, events[8].Err, &p)
+	require.Equal(t, int64(1), e.(ExecutorStats).Stats().Panicked)
+}
+
+func Test_WrapExecutorPanic(t *testing.T) {
+	var kinds List[TaskEventKind]
+	e := WrapExecutor(NewExecutor(-1, WithPanicPolicy(PanicCapture)), []Interceptor{InterceptorFunc(func(event TaskEvent) {
+		kinds.Append(event.Kind)
+	})}).(ErrorExecutor)
+
+	// panics are raised again to be handled by the underlying executor
+	e.Go(func() {
+		panic("panicked")
+	})
+	var p PanicError
+	require.ErrorAs(t, e.WaitErr(context.Background()), &p)
+	require.Equal(t, []TaskEventKind{TaskSubmitted, TaskStarted, TaskPanicked}, kinds.Values())
+}
+
+func Test_WrapExecutorSkipped(t *testing.T) {
+	var skipped List[uint64]
+	e := WrapExecutor(NewQueuedExecutor(1, WithDrainTimeout(-1)), []Interceptor{InterceptorFunc(func(event TaskEvent) {
+		if event.Kind == TaskSkipped {
+			skipped.Append(event.ID)
+		}
+	})})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	e.Go(func() {
+		close(started)
+		<-release
+	})
+	<-started
+	executed := false
+	e.Go(func() {
+		executed = true
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	e.Wait(ctx)
+	close(release)
+	e.Wait(context.Background())
+
+	require.False(t, executed)
+	require.Equal(t, []uint64{2}, skipped.Values())
+}
+
+func Test_WrapExecutorRejected(t *testing.T) {
+	executor := NewExecutor(-1)
+	require.NoError(t, executor.(ShutdownExecutor).Close())
+	var kinds List[TaskEventKind]
+	e := WrapExecutor(executor, []Interceptor{InterceptorFunc(func(event TaskEvent) {
+		kinds.Append(event.Kind)
+	})}).(SkipExecutor)
+
+	// units of work the underlying executor does not execute are reported as skipped once, and not left pending
+	var errs List[error]
+	e.GoSkip(func() {}, func(err error) {
+		errs.Append(err)
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	e.Wait(ctx)
+
+	require.Equal(t, []TaskEventKind{TaskSubmitted, TaskSkipped}, kinds.Values())
+	require.Equal(t, []error{ErrExecutorClosed}, errs.Values())
+	require.Empty(t, e.(*interceptedExecutor).pending)
+}
+
+func Test_WrapExecutorContext(t *testing.T) {
+	var kinds List[TaskEventKind]
+	ctx := SetContextExecutor(context.Background(), "", WrapExecutor(NewExecutor(2), []Interceptor{InterceptorFunc(func(event TaskEvent) {
+		kinds.Append(event.Kind)
+	})}))
+
+	e := ContextExecutor(&ctx, "")
+	e.(ContextualExecutor).GoCtx(func(context.Context) {
+		// the child executor is wrapped with the same interceptors
+		child := ContextExecutor(&ctx, "")
+		child.Go(func() {})
+		child.Wait(context.Background())
+	})
+	e.Wait(context.Background())
+
+	require.Len(t, kinds.Values(), 6)
+	require.Equal(t, "skipped", TaskSkipped.String())
+}
+
+func Test_WrapExecutorFailFast(t *testing.T) {
+	var kinds List[TaskEventKind]
+	e := WrapExecutor(NewExecutor(0), []Interceptor{InterceptorFunc(func(event TaskEvent) {
+		kinds.Append(event.Kind)
+	})}, WithFailFast()).(ErrorExecutor)
+
+	e.GoErr(func() error {
+		return fmt.Errorf("failed")
+	})
+	executed := false
+	var errs List[error]
+	e.(SkipExecutor).GoSkip(func() {
+		executed = true
+	}, func(err error) {
+		errs.Append(err)
+	})
+	require.ErrorContains(t, e.WaitErr(context.Background()), "failed")
+
+	require.False(t, executed)
+	require.Equal(t, []error{ErrSkipped}, errs.Values())
+	require.Equal(t, []TaskEventKind{TaskSubmitted, TaskStarted, TaskFinished, TaskSubmitted, TaskSkipped}, kinds.Values())
+}
@@ -0,0 +1,285 @@
+package sync
+
+import (
+	"context"
+	"sync"
+)
+
+// KeyedExecutor executes units of work with the same key one at a time, in the order they were submitted, while
+// units of work with different keys execute in parallel
+type KeyedExecutor[K comparable] interface {
+	// Go adds a unit of work to be executed after all previously added units of work with the same key
+	Go(key K, fn func())
+
+	// Wait blocks until all units of work have finished, or the context is canceled. If the context is canceled,
+	// queued units of work are skipped
+	Wait(ctx context.Context)
+
+	// WaitErr is like Wait, additionally returning the errors reported since the previous WaitErr call, joined,
+	// such as panics captured with the PanicCapture policy or units of work the executor did not execute
+	WaitErr(ctx context.Context) error
+
+	// Stats returns the statistics of each key which has units of work queued or executing, keys are removed once
+	// they have no more units of work
+	Stats() map[K]KeyStats
+}
+
+// KeyStats are the statistics of the units of work for a single key of a KeyedExecutor
+type KeyStats struct {
+	// Queued is the number of units of work waiting to execute
+	Queued int
+
+	// PeakQueued is the highest number of units of work waiting to execute at once
+	PeakQueued int
+
+	// Active is true when a unit of work for the key is executing
+	Active bool
+
+	// Completed is the number of units of work which finished executing
+	Completed int64
+}
+
+// keyedExecutor is a KeyedExecutor which executes units of work using another executor, processing at most
+// maxConcurrency keys at a time
+type keyedExecutor[K comparable] struct {
+	config         executorConfig
+	executor       Executor
+	maxConcurrency int
+	life           lifecycle
+	errs           taskErrors
+	tasks          taskContexts
+	lock           sync.Mutex
+	keys           map[K]*keyQueue
+	ready          List[K]
+	executing      int
+}
+
+// keyQueue holds the units of work waiting to execute for a single key
+type keyQueue struct {
+	tasks     List[*keyedTask]
+	scheduled bool
+	stats     KeyStats
+}
+
+// keyedTask is a unit of work waiting to execute in a keyQueue
+type keyedTask struct {
+	fn    func()
+	group *taskGroup
+	id    uint64
+}
+
+var _ KeyedExecutor[string] = (*keyedExecutor[string])(nil)
+
+// NewKeyedExecutor returns a KeyedExecutor which executes units of work using the provided executor, with at most
+// maxConcurrency keys executing at a time, a maxConcurrency < 1 is unbounded. Units of work for keys waiting
+// to execute are started in the order the keys became ready, each key executing one unit of work at a time
+func NewKeyedExecutor[K comparable](executor Executor, maxConcurrency int, opts ...Option) KeyedExecutor[K] {
+	cfg := newExecutorConfig(opts...)
+	e := &keyedExecutor[K]{
+		config:         cfg,
+		executor:       executor,
+		maxConcurrency: maxConcurrency,
+		life:           newLifecycle(cfg),
+		errs:           newTaskErrors(cfg),
+		tasks:          newTaskContexts(cfg),
+		keys:           map[K]*keyQueue{},
+	}
+	e.life.drain = e.drain
+	return e
+}
+
+func (e *keyedExecutor[K]) Go(key K, fn func()) {
+	group, id, ok := e.life.add(fn)
+	if !ok {
+		e.errs.record(ErrExecutorClosed)
+		e.config.reject(ErrExecutorClosed)
+		return
+	}
+
+	e.lock.Lock()
+	q := e.keys[key]
+	if q == nil {
+		q = &keyQueue{}
+		e.keys[key] = q
+	}
+	q.tasks.Enqueue(&keyedTask{fn: fn, group: group, id: id})
+	q.stats.Queued++
+	q.stats.PeakQueued = max(q.stats.PeakQueued, q.stats.Queued)
+	if !q.scheduled {
+		// a key is only in the ready list when no unit of work for the key is executing
+		q.scheduled = true
+		e.ready.Enqueue(key)
+	}
+	e.lock.Unlock()
+
+	e.schedule()
+}
+
+func (e *keyedExecutor[K]) Wait(ctx context.Context) {
+	_, _ = e.life.wait(ctx, &e.tasks)
+}
+
+func (e *keyedExecutor[K]) WaitErr(ctx context.Context) error {
+	e.Wait(ctx)
+	return e.errs.take()
+}
+
+func (e *keyedExecutor[K]) Stats() map[K]KeyStats {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	out := make(map[K]KeyStats, len(e.keys))
+	for key, q := range e.keys {
+		out[key] = q.stats
+	}
+	return out
+}
+
+// schedule submits a function to the executor to process ready keys, if fewer than maxConcurrency are executing
+func (e *keyedExecutor[K]) schedule() {
+	e.lock.Lock()
+	start := e.ready.Len() > 0 && (e.maxConcurrency < 1 || e.executing < e.maxConcurrency)
+	if start {
+		e.executing++
+	}
+	e.lock.Unlock()
+
+	if start {
+		// the executor may block or execute directly, so it must be called without the lock held
+		goSkip(e.executor, e.exec, e.rejected)
+	}
+}
+
+// rejected is called when the executor does not execute a function submitted by schedule. When no other
+// function is processing ready keys, the queued units of work are skipped rather than waiting indefinitely
+func (e *keyedExecutor[K]) rejected(err error) {
+	e.errs.record(err)
+	e.lock.Lock()
+	e.executing--
+	var skipped []*keyedTask
+	if e.executing == 0 {
+		for key, ok := e.ready.Dequeue(); ok; key, ok = e.ready.Dequeue() {
+			q := e.keys[key]
+			for task, ok := q.tasks.Dequeue(); ok; task, ok = q.tasks.Dequeue() {
+				skipped = append(skipped, task)
+			}
+			q.stats.Queued = 0
+			q.scheduled = false
+			e.evict(key, q)
+		}
+	}
+	e.lock.Unlock()
+
+	for _, task := range skipped {
+		task.group.skip(task.id)
+		task.group.wg.Done()
+	}
+}
+
+// exec executes units of work from ready keys until none are ready. After each unit of work the key is returned
+// to the end of the ready list, so keys with many units of work do not prevent other keys from executing
+func (e *keyedExecutor[K]) exec() {
+	defer func() {
+		if v := recover(); v != nil {
+			// this function exits when a unit of work panics with the PanicRepanic policy, so schedule another
+			e.lock.Lock()
+			e.executing--
+			e.lock.Unlock()
+			e.schedule()
+			panic(v)
+		}
+	}()
+	for {
+		key, task, ok := e.next()
+		if !ok {
+			return
+		}
+		e.run(key, task)
+	}
+}
+
+// next returns the next unit of work to execute, or false when no keys are ready and the caller should exit
+func (e *keyedExecutor[K]) next() (K, *keyedTask, bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	for {
+		key, ok := e.ready.Dequeue()
+		if !ok {
+			e.executing--
+			return key, nil, false
+		}
+		q := e.keys[key]
+		task, ok := q.tasks.Dequeue()
+		if !ok {
+			// all units of work for the key were skipped
+			q.scheduled = false
+			e.evict(key, q)
+			continue
+		}
+		q.stats.Queued--
+		q.stats.Active = true
+		return key, task, true
+	}
+}
+
+// run executes the unit of work, then returns the key to the ready list if it has more units of work
+func (e *keyedExecutor[K]) run(key K, task *keyedTask) {
+	defer task.group.wg.Done()
+	if !task.group.start(task.id, false) {
+		e.done(key, false)
+		return
+	}
+	defer task.group.finish()
+	defer e.done(key, true)
+	e.errs.call(task.fn)
+}
+
+// done updates the key after a unit of work finishes or is skipped
+func (e *keyedExecutor[K]) done(key K, completed bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	q := e.keys[key]
+	q.stats.Active = false
+	if completed {
+		q.stats.Completed++
+	}
+	if q.tasks.Len() > 0 {
+		e.ready.Enqueue(key)
+	} else {
+		q.scheduled = false
+		e.evict(key, q)
+	}
+}
+
+// evict removes a key which has no units of work queued or executing, so keys which are no longer used do not
+// accumulate, must be called with the lock held
+func (e *keyedExecutor[K]) evict(key K, q *keyQueue) {
+	if !q.scheduled && !q.stats.Active && q.tasks.Len() == 0 {
+		delete(e.keys, key)
+	}
+}
+
+// drain removes units of work belonging to canceled groups from each key
+func (e *keyedExecutor[K]) drain() {
+	e.lock.Lock()
+	var skipped []*keyedTask
+	for _, q := range e.keys {
+		var kept []*keyedTask
+		for task, ok := q.tasks.Dequeue(); ok; task, ok = q.tasks.Dequeue() {
+			if task.group.isCanceled() {
+				skipped = append(skipped, task)
+			} else {
+				kept = append(kept, task)
+			}
+		}
+		for _, task := range kept {
+			q.tasks.Enqueue(task)
+		}
+		q.stats.Queued = len(kept)
+	}
+	e.lock.Unlock()
+
+	for _, task := range skipped {
+		task.group.start(task.id, false)
+		task.group.wg.Done()
+	}
+}
@@ -0,0 +1,199 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/go-sync/internal/stats"
+)
+
+func Test_KeyedExecutor(t *testing.T) {
+	const count = 100
+	keys := []string{"a", "b", "c", "d"}
+
+	tests := []struct {
+		name     string
+		executor Executor
+	}{
+		{
+			name:     "serial",
+			executor: NewExecutor(0),
+		},
+		{
+			name:     "unbounded",
+			executor: NewExecutor(-1),
+		},
+		{
+			name:     "bounded",
+			executor: NewExecutor(2),
+		},
+		{
+			name:     "queued",
+			executor: NewQueuedExecutor(4),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := NewKeyedExecutor[string](test.executor, 2)
+
+			executing := stats.Tracked[int]{}
+			overlapped := atomic.Bool{}
+			perKey := map[string]*atomic.Int32{}
+			values := map[string]*List[int]{}
+			for _, key := range keys {
+				perKey[key] = &atomic.Int32{}
+				values[key] = &List[int]{}
+			}
+			for i := range count {
+				for _, key := range keys {
+					e.Go(key, func() {
+						defer executing.Incr()()
+						if perKey[key].Add(1) != 1 {
+							overlapped.Store(true)
+						}
+						defer perKey[key].Add(-1)
+						values[key].Append(i)
+					})
+				}
+			}
+			e.Wait(context.Background())
+			test.executor.Wait(context.Background())
+
+			require.LessOrEqual(t, executing.Max(), 2)
+			require.False(t, overlapped.Load())
+			for _, key := range keys {
+				expected := make([]int, count)
+				for i := range expected {
+					expected[i] = i
+				}
+				require.Equal(t, expected, values[key].Values())
+			}
+			// keys without units of work are removed
+			require.Empty(t, e.Stats())
+		})
+	}
+}
+
+func Test_KeyedExecutorStats(t *testing.T) {
+	e := NewKeyedExecutor[int](NewExecutor(-1), 0)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	e.Go(1, func() {
+		close(started)
+		<-release
+	})
+	<-started
+	for range 3 {
+		e.Go(1, func() {})
+	}
+	e.Go(2, func() {})
+
+	// key 2 is removed once its unit of work completes
+	require.Eventually(t, func() bool {
+		_, ok := e.Stats()[2]
+		return !ok
+	}, time.Second, time.Millisecond)
+
+	s := e.Stats()[1]
+	require.True(t, s.Active)
+	require.Equal(t, 3, s.Queued)
+	require.Equal(t, 3, s.PeakQueued)
+
+	close(release)
+	e.Wait(context.Background())
+	require.Empty(t, e.Stats())
+}
+
+func Test_KeyedExecutorStatsCompleted(t *testing.T) {
+	e := NewKeyedExecutor[int](NewExecutor(-1), 0)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	e.Go(1, func() {
+		close(started)
+		<-release
+	})
+	<-started
+	completed := make(chan struct{})
+	next := make(chan struct{})
+	e.Go(1, func() {
+		close(completed)
+		<-next
+	})
+
+	// the key is retained while it has units of work, so completed units of work are counted
+	close(release)
+	<-completed
+	s := e.Stats()[1]
+	require.True(t, s.Active)
+	require.Equal(t, int64(1), s.Completed)
+	close(next)
+	e.Wait(context.Background())
+}
+
+func Test_KeyedExecutorWaitErr(t *testing.T) {
+	e := NewKeyedExecutor[string](NewExecutor(-1), 0, WithPanicPolicy(PanicCapture))
+	e.Go("a", func() {
+		panic("a panicked")
+	})
+	e.Go("a", func() {})
+
+	var p PanicError
+	require.ErrorAs(t, e.WaitErr(context.Background()), &p)
+	require.Equal(t, "a panicked", p.Value)
+	require.NoError(t, e.WaitErr(context.Background()))
+}
+
+func Test_KeyedExecutorRejected(t *testing.T) {
+	executor := NewExecutor(-1)
+	require.NoError(t, executor.(ShutdownExecutor).Close())
+	e := NewKeyedExecutor[string](executor, 0)
+
+	// units of work are not waited on when the executor does not execute them
+	executed := atomic.Bool{}
+	e.Go("a", func() {
+		executed.Store(true)
+	})
+	require.ErrorIs(t, e.WaitErr(context.Background()), ErrExecutorClosed)
+	require.False(t, executed.Load())
+	require.Empty(t, e.Stats())
+}
+
+func Test_KeyedExecutorCancel(t *testing.T) {
+	// Wait returns without waiting for the executing unit of work, which is released afterward
+	e := NewKeyedExecutor[string](NewExecutor(-1, WithDrainTimeout(-1)), 1, WithDrainTimeout(-1))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	e.Go("a", func() {
+		close(started)
+		<-release
+	})
+	<-started
+
+	executed := atomic.Int32{}
+	for i := range 3 {
+		e.Go(fmt.Sprint(i), func() {
+			executed.Add(1)
+		})
+	}
+
+	cancel()
+	e.Wait(ctx)
+	close(release)
+
+	// subsequent units of work are executed
+	e.Go("a", func() {
+		executed.Add(10)
+	})
+	e.Wait(context.Background())
+	require.Equal(t, int32(10), executed.Load())
+	require.Equal(t, 0, e.Stats()["0"].Queued)
+}
@@ -0,0 +1,349 @@
+package sync
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ShutdownExecutor is implemented by executors which can be shut down, after which units of work are rejected
+type ShutdownExecutor interface {
+	Executor
+
+	// Shutdown stops accepting units of work and waits for those already submitted to finish. If the context is
+	// canceled first, units of work not yet started are skipped, the contexts provided to GoCtx functions are
+	// canceled and the context error is returned. Any errors which would be reported by WaitErr are also returned
+	Shutdown(ctx context.Context) error
+
+	// Close calls Shutdown, waiting for units of work to finish for up to the timeout set with WithDrainTimeout,
+	// or indefinitely if no timeout is set
+	Close() error
+
+	// State returns the current lifecycle state
+	State() ExecutorState
+}
+
+// ExecutorState is the lifecycle state of an executor
+type ExecutorState int32
+
+const (
+	// ExecutorRunning accepts and executes units of work
+	ExecutorRunning ExecutorState = iota
+
+	// ExecutorDraining rejects new units of work while waiting for submitted units of work to finish
+	ExecutorDraining
+
+	// ExecutorClosed rejects all units of work
+	ExecutorClosed
+)
+
+func (s ExecutorState) String() string {
+	switch s {
+	case ExecutorRunning:
+		return "running"
+	case ExecutorDraining:
+		return "draining"
+	case ExecutorClosed:
+		return "closed"
+	}
+	return "unknown"
+}
+
+// ResultExecutor is implemented by executors which report the outcome of the units of work waited on
+type ResultExecutor interface {
+	Executor
+
+	// WaitResult waits like Wait, returning the number of units of work completed, skipped and still running
+	// since the previous call to Wait or WaitResult
+	WaitResult(ctx context.Context) WaitResult
+}
+
+// WaitResult is the outcome of units of work submitted to an executor
+type WaitResult struct {
+	// Completed is the number of units of work which finished executing
+	Completed int
+
+	// Skipped is the number of units of work which were not executed, due to a canceled context or failing fast
+	Skipped int
+
+	// Running is the number of units of work still executing when WaitResult returned, after a canceled context
+	Running int
+
+	// SkippedTasks are the functions which were not executed, only provided when using WithRetainSkipped. Functions
+	// submitted with GoErr or GoCtx are wrapped, and report errors and observe cancellation when resubmitted to
+	// the same executor
+	SkippedTasks []func()
+}
+
+// taskGroup is a generation of submitted units of work, which are skipped together when a call to Wait observes
+// a canceled context
+type taskGroup struct {
+	retain    bool
+	wg        sync.WaitGroup
+	lock      sync.Mutex
+	canceled  bool
+	next      uint64
+	pending   map[uint64]func()
+	submitted int
+	running   int
+	completed int
+	skipped   int
+	skips     []func()
+}
+
+// add adds a unit of work to the group, returning its id, must be called with the lifecycle lock held
+func (g *taskGroup) add(fn func()) uint64 {
+	g.wg.Add(1)
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.next++
+	g.submitted++
+	if g.retain {
+		if g.pending == nil {
+			g.pending = map[uint64]func(){}
+		}
+		g.pending[g.next] = fn
+	}
+	return g.next
+}
+
+// start returns true if the unit of work should execute, false if it was skipped because the group was canceled
+// or skip is true. Units of work which start must call finish, and all must call wg.Done
+func (g *taskGroup) start(id uint64, skip bool) bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	fn := g.pending[id]
+	delete(g.pending, id)
+	if g.canceled {
+		// counted when canceled
+		return false
+	}
+	if skip {
+		g.skipped++
+		if fn != nil {
+			g.skips = append(g.skips, fn)
+		}
+		return false
+	}
+	g.running++
+	return true
+}
+
+// skip records a unit of work which was not executed
+func (g *taskGroup) skip(id uint64) {
+	g.start(id, true)
+}
+
+// remove removes a unit of work which was not accepted by the executor
+func (g *taskGroup) remove(id uint64) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	delete(g.pending, id)
+	g.submitted--
+}
+
+// finish records a unit of work which finished executing
+func (g *taskGroup) finish() {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.running--
+	g.completed++
+}
+
+// cancel causes all units of work in the group not yet started to be skipped
+func (g *taskGroup) cancel() {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if g.canceled {
+		return
+	}
+	g.canceled = true
+	g.skipped += g.submitted - g.completed - g.skipped - g.running
+	// retain skipped functions in the order they were submitted
+	for _, id := range slices.Sorted(maps.Keys(g.pending)) {
+		g.skips = append(g.skips, g.pending[id])
+	}
+	g.pending = nil
+}
+
+// isCanceled returns true when the group has been canceled
+func (g *taskGroup) isCanceled() bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	return g.canceled
+}
+
+// result returns the outcome of units of work since the previous call
+func (g *taskGroup) result() WaitResult {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	r := WaitResult{
+		Completed:    g.completed,
+		Skipped:      g.skipped,
+		Running:      g.running,
+		SkippedTasks: g.skips,
+	}
+	g.submitted -= g.completed + g.skipped
+	g.completed, g.skipped, g.skips = 0, 0, nil
+	return r
+}
+
+// done returns a channel closed when all units of work in the group have finished
+func (g *taskGroup) done() <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+	return done
+}
+
+// lifecycle tracks the state of an executor and the taskGroup units of work are added to. When a call to Wait
+// observes a canceled context, a new taskGroup is started, so units of work submitted afterward are executed and
+// waiting for them does not reuse a sync.WaitGroup which may still be waited on
+type lifecycle struct {
+	clock        Clock
+	drainTimeout time.Duration
+	retain       bool
+	drain        func()
+	lock         sync.Mutex
+	state        ExecutorState
+	group        *taskGroup
+}
+
+func newLifecycle(config executorConfig) lifecycle {
+	return lifecycle{
+		clock:        config.getClock(),
+		drainTimeout: config.drainTimeout,
+		retain:       config.retainSkipped,
+	}
+}
+
+// add adds a unit of work to the current group, returning the group and the id of the unit of work, or false if
+// the executor is not accepting units of work
+func (l *lifecycle) add(fn func()) (*taskGroup, uint64, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.state != ExecutorRunning {
+		return nil, 0, false
+	}
+	g := l.current()
+	return g, g.add(fn), true
+}
+
+// accepting returns true when the executor is accepting units of work, used by executors which do not track groups
+func (l *lifecycle) accepting() bool {
+	return l.State() == ExecutorRunning
+}
+
+// current returns the current group, must be called with the lock held
+func (l *lifecycle) current() *taskGroup {
+	if l.group == nil {
+		l.group = &taskGroup{retain: l.retain}
+	}
+	return l.group
+}
+
+// State returns the current lifecycle state
+func (l *lifecycle) State() ExecutorState {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.state
+}
+
+// setState changes the lifecycle state, never returning to an earlier state
+func (l *lifecycle) setState(state ExecutorState) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.state = max(l.state, state)
+}
+
+// wait waits for the current group of units of work to finish. If the context is canceled first, the group is
+// canceled, skipping units of work not yet started, and executing units of work are waited on until they finish
+// when the drain timeout is 0, for up to the drain timeout when it is positive, or not at all when it is negative.
+// Returns the context error when canceled
+func (l *lifecycle) wait(ctx context.Context, tasks *taskContexts) (WaitResult, error) {
+	return l.waitFor(ctx, tasks, l.drainTimeout)
+}
+
+func (l *lifecycle) waitFor(ctx context.Context, tasks *taskContexts, drainTimeout time.Duration) (WaitResult, error) {
+	l.lock.Lock()
+	g := l.current()
+	l.lock.Unlock()
+
+	canceled := tasks.wait(ctx)
+	done := g.done()
+	if ctx.Err() == nil {
+		select {
+		case <-done:
+			return g.result(), nil
+		case <-ctx.Done():
+		}
+	}
+
+	l.lock.Lock()
+	g.cancel()
+	if l.group == g {
+		l.group = nil
+	}
+	l.lock.Unlock()
+	if l.drain != nil {
+		l.drain()
+	}
+	canceled()
+	// units of work submitted after this point receive a new context
+	tasks.reset()
+
+	switch {
+	case drainTimeout == 0:
+		<-done
+	case drainTimeout > 0:
+		timer := l.clock.NewTimer(drainTimeout)
+		defer timer.Stop()
+		select {
+		case <-done:
+		case <-timer.C():
+		}
+	}
+	return g.result(), ctx.Err()
+}
+
+// shutdown stops accepting units of work and waits for the current group to finish, the context provides the
+// deadline to drain units of work, so executing units of work are not waited on once it is canceled
+func (l *lifecycle) shutdown(ctx context.Context, tasks *taskContexts) error {
+	l.setState(ExecutorDraining)
+	defer l.setState(ExecutorClosed)
+	_, err := l.waitFor(ctx, tasks, -1)
+	return err
+}
+
+// closeContext returns the context used by Close, which is canceled after the drain timeout, if set, elapses on
+// the clock
+func (l *lifecycle) closeContext() (context.Context, context.CancelFunc) {
+	if l.drainTimeout > 0 {
+		return withClockTimeout(context.Background(), l.clock, l.drainTimeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// shutdownChild shuts down a child executor which was created by the parent executor
+func shutdownChild(ctx context.Context, parent Executor, lock *sync.RWMutex, child *Executor) error {
+	lock.RLock()
+	executor := *child
+	lock.RUnlock()
+	if e, ok := executor.(ShutdownExecutor); ok && executor != parent {
+		return e.Shutdown(ctx)
+	}
+	return nil
+}
+
+// rejectClosed reports a unit of work submitted to an executor which is not accepting units of work, including
+// to the submitter when skipped is provided
+func rejectClosed(config *executorConfig, errs *taskErrors, stats *taskStats, skipped func(error)) {
+	stats.rejected.Add(1)
+	errs.record(ErrExecutorClosed)
+	config.reject(ErrExecutorClosed)
+	skipTask(skipped, ErrExecutorClosed)
+}
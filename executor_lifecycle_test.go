@@ -0,0 +1,270 @@
+package sync
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShutdownExecutor(t *testing.T) {
+	for _, test := range allOptionExecutors() {
+		t.Run(test.name, func(t *testing.T) {
+			rejected := List[error]{}
+			e := test.executor(WithRejectionHandler(func(err error) {
+				rejected.Append(err)
+			})).(ShutdownExecutor)
+			require.Equal(t, ExecutorRunning, e.State())
+
+			executed := atomic.Int32{}
+			for range 3 {
+				e.Go(func() {
+					time.Sleep(time.Millisecond)
+					executed.Add(1)
+				})
+			}
+			require.NoError(t, e.Shutdown(context.Background()))
+			require.Equal(t, int32(3), executed.Load())
+			require.Equal(t, ExecutorClosed, e.State())
+
+			e.Go(func() {
+				executed.Add(1)
+			})
+			e.Wait(context.Background())
+			require.Equal(t, int32(3), executed.Load())
+			require.Equal(t, []error{ErrExecutorClosed}, rejected.Values())
+			require.ErrorIs(t, e.(ErrorExecutor).WaitErr(context.Background()), ErrExecutorClosed)
+			require.Equal(t, int64(1), e.(ExecutorStats).Stats().Rejected)
+		})
+	}
+}
+
+func Test_SkipExecutor(t *testing.T) {
+	for _, test := range allOptionExecutors() {
+		t.Run(test.name, func(t *testing.T) {
+			e := test.executor(WithFailFast(), WithPanicPolicy(PanicCapture)).(SkipExecutor)
+			executed := atomic.Bool{}
+			skipped := make(chan error, 1)
+
+			// failing fast skips units of work until the error is reported
+			e.Go(func() {
+				panic("failed")
+			})
+			e.Wait(context.Background())
+			e.GoSkip(func() {
+				executed.Store(true)
+			}, func(err error) {
+				skipped <- err
+			})
+			e.Wait(context.Background())
+			require.ErrorIs(t, <-skipped, ErrSkipped)
+			require.Error(t, e.(ErrorExecutor).WaitErr(context.Background()))
+
+			// units of work submitted after closing are rejected
+			require.NoError(t, e.(ShutdownExecutor).Close())
+			e.GoSkip(func() {
+				executed.Store(true)
+			}, func(err error) {
+				skipped <- err
+			})
+			require.ErrorIs(t, <-skipped, ErrExecutorClosed)
+			require.False(t, executed.Load())
+		})
+	}
+}
+
+func Test_ShutdownCanceled(t *testing.T) {
+	e := NewQueuedExecutor(1).(ShutdownExecutor)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	e.Go(func() {
+		close(started)
+		<-release
+	})
+	executed := atomic.Bool{}
+	e.Go(func() {
+		executed.Store(true)
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, e.Shutdown(ctx), context.DeadlineExceeded)
+	require.Equal(t, ExecutorClosed, e.State())
+
+	close(release)
+	require.Eventually(t, func() bool {
+		return e.(ExecutorStats).Stats().Canceled == 1
+	}, time.Second, time.Millisecond)
+	require.False(t, executed.Load())
+}
+
+func Test_CloseDrainTimeout(t *testing.T) {
+	e := NewExecutor(1, WithDrainTimeout(10*time.Millisecond)).(ShutdownExecutor)
+
+	release := make(chan struct{})
+	defer close(release)
+	e.Go(func() {
+		<-release
+	})
+
+	start := time.Now()
+	require.ErrorIs(t, e.Close(), context.DeadlineExceeded)
+	require.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+}
+
+func Test_CloseDrainTimeoutClock(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	e := NewExecutor(1, WithDrainTimeout(time.Hour), WithClock(clock)).(ShutdownExecutor)
+
+	release := make(chan struct{})
+	defer close(release)
+	e.Go(func() {
+		<-release
+	})
+
+	closed := make(chan error)
+	go func() {
+		closed <- e.Close()
+	}()
+	require.NoError(t, clock.BlockUntil(context.Background(), 1))
+	clock.Advance(2 * time.Hour)
+	require.ErrorIs(t, <-closed, context.DeadlineExceeded)
+}
+
+func Test_WaitDrainTimeout(t *testing.T) {
+	for _, timeout := range []time.Duration{0, time.Minute} {
+		t.Run(timeout.String(), func(t *testing.T) {
+			e := NewExecutor(-1, WithDrainTimeout(timeout)).(ContextualExecutor)
+			ctx, cancel := context.WithCancel(context.Background())
+
+			started := make(chan struct{})
+			exited := atomic.Bool{}
+			e.GoCtx(func(ctx context.Context) {
+				close(started)
+				<-ctx.Done()
+				exited.Store(true)
+			})
+			<-started
+
+			cancel()
+			e.Wait(ctx)
+			// the unit of work observed cancellation and exited before Wait returned
+			require.True(t, exited.Load())
+
+			// subsequent units of work are executed after a canceled Wait
+			executed := atomic.Bool{}
+			e.Go(func() {
+				executed.Store(true)
+			})
+			e.Wait(context.Background())
+			require.True(t, executed.Load())
+		})
+	}
+}
+
+func Test_WaitDrainTimeoutElapsed(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	for _, timeout := range []time.Duration{time.Hour, -1} {
+		t.Run(timeout.String(), func(t *testing.T) {
+			e := NewExecutor(-1, WithDrainTimeout(timeout), WithClock(clock))
+			ctx, cancel := context.WithCancel(context.Background())
+
+			release := make(chan struct{})
+			defer close(release)
+			started := make(chan struct{})
+			e.Go(func() {
+				close(started)
+				<-release
+			})
+			<-started
+
+			cancel()
+			waited := make(chan struct{})
+			go func() {
+				defer close(waited)
+				e.Wait(ctx)
+			}()
+			if timeout > 0 {
+				// the unit of work ignores cancellation, so Wait returns once the drain timeout elapses
+				require.NoError(t, clock.BlockUntil(context.Background(), 1))
+				clock.Advance(timeout)
+			}
+			<-waited
+		})
+	}
+}
+
+func Test_ResultExecutor(t *testing.T) {
+	for _, test := range allOptionExecutors() {
+		t.Run(test.name, func(t *testing.T) {
+			e := test.executor(WithFailFast(), WithPanicPolicy(PanicCapture), WithRetainSkipped()).(ResultExecutor)
+
+			for range 3 {
+				e.Go(func() {})
+			}
+			result := e.WaitResult(context.Background())
+			require.Equal(t, WaitResult{Completed: 3}, result)
+
+			e.Go(func() {
+				panic("failed")
+			})
+			e.Wait(context.Background())
+
+			// failing fast skips units of work until the error is reported
+			executed := false
+			e.Go(func() {
+				executed = true
+			})
+			result = e.WaitResult(context.Background())
+			require.Equal(t, 0, result.Completed)
+			require.Equal(t, 1, result.Skipped)
+			require.Len(t, result.SkippedTasks, 1)
+			require.Error(t, e.(ErrorExecutor).WaitErr(context.Background()))
+
+			// skipped functions can be resubmitted
+			e.Go(result.SkippedTasks[0])
+			require.Equal(t, WaitResult{Completed: 1}, e.WaitResult(context.Background()))
+			require.True(t, executed)
+		})
+	}
+}
+
+func Test_WaitResultCanceled(t *testing.T) {
+	// Wait returns without waiting for the executing unit of work, so it is reported as running
+	e := NewQueuedExecutor(1, WithRetainSkipped(), WithDrainTimeout(-1)).(ResultExecutor)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	e.Go(func() {
+		close(started)
+		<-release
+	})
+	<-started
+
+	executed := List[int]{}
+	for i := range 3 {
+		e.Go(func() {
+			executed.Append(i)
+		})
+	}
+
+	cancel()
+	result := e.WaitResult(ctx)
+	require.Equal(t, 0, result.Completed)
+	require.Equal(t, 1, result.Running)
+	require.Equal(t, 3, result.Skipped)
+	require.Len(t, result.SkippedTasks, 3)
+	require.Empty(t, executed.Values())
+
+	close(release)
+	for _, fn := range result.SkippedTasks {
+		e.Go(fn)
+	}
+	require.Equal(t, WaitResult{Completed: 3}, e.WaitResult(context.Background()))
+	require.Equal(t, []int{0, 1, 2}, executed.Values())
+}
@@ -0,0 +1,202 @@
+package sync
+
+import "time"
+
+// Option configures optional behavior of executors created with the New*Executor functions
+type Option func(*executorConfig)
+
+// RejectionPolicy determines what happens when a unit of work is submitted to an executor with a full queue
+type RejectionPolicy int
+
+const (
+	// RejectBlock blocks the caller of Go until there is space in the queue
+	RejectBlock RejectionPolicy = iota
+
+	// RejectDrop discards the unit of work without reporting an error. Submitters using SkipExecutor.GoSkip, such as
+	// Collect, Submit and SingleFlight, are still notified with ErrQueueFull; other submitters waiting for the unit
+	// of work to execute, such as with a sync.WaitGroup, wait indefinitely
+	RejectDrop
+
+	// RejectError discards the unit of work and reports ErrQueueFull to the rejection handler, from WaitErr and to
+	// submitters using SkipExecutor.GoSkip
+	RejectError
+)
+
+// ChildStrategy determines what kind of executor is returned from ChildExecutor
+type ChildStrategy int
+
+const (
+	// ChildBounded returns a blocking executor bounded to the same concurrency as the parent
+	ChildBounded ChildStrategy = iota
+
+	// ChildQueued returns a queued executor with the same concurrency and options as the parent
+	ChildQueued
+
+	// ChildSerial returns an executor which runs nested work directly in the calling goroutine
+	ChildSerial
+
+	// ChildSelf returns the same executor; this is only safe when units of work never wait on nested work
+	ChildSelf
+)
+
+// PanicPolicy determines how executors handle a panic raised by a unit of work submitted with Go
+type PanicPolicy int
+
+const (
+	// PanicRepanic recovers the panic and panics again with a PanicError, including the original stack
+	PanicRepanic PanicPolicy = iota
+
+	// PanicHandle recovers the panic and calls the handler provided with WithPanicHandler
+	PanicHandle
+
+	// PanicCapture recovers the panic and reports the PanicError from WaitErr
+	PanicCapture
+)
+
+// executorConfig holds the optional configuration shared by executor implementations
+type executorConfig struct {
+	queueCapacity    int
+	rejection        RejectionPolicy
+	rejectionHandler func(error)
+	childStrategy    ChildStrategy
+	failFast         bool
+	panicPolicy      PanicPolicy
+	panicHandler     func(PanicError)
+	taskTimeout      time.Duration
+	priorityAging    time.Duration
+	drainTimeout     time.Duration
+	retainSkipped    bool
+	clock            Clock
+}
+
+func newExecutorConfig(opts ...Option) executorConfig {
+	cfg := executorConfig{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	return cfg
+}
+
+// WithQueueCapacity limits the number of queued units of work waiting to execute, a capacity <= 0 is unlimited
+func WithQueueCapacity(capacity int) Option {
+	return func(c *executorConfig) {
+		c.queueCapacity = capacity
+	}
+}
+
+// WithRejectionPolicy sets the behavior when Go is called and the queue is at capacity
+func WithRejectionPolicy(policy RejectionPolicy) Option {
+	return func(c *executorConfig) {
+		c.rejection = policy
+	}
+}
+
+// WithRejectionHandler sets a function to be called with the reason a unit of work was not accepted
+func WithRejectionHandler(handler func(error)) Option {
+	return func(c *executorConfig) {
+		c.rejectionHandler = handler
+	}
+}
+
+// WithChildStrategy sets the kind of executor returned when ChildExecutor is called
+func WithChildStrategy(strategy ChildStrategy) Option {
+	return func(c *executorConfig) {
+		c.childStrategy = strategy
+	}
+}
+
+// WithFailFast causes units of work not yet started to be skipped after any GoErr function returns an error or
+// panics, until the errors are reported by WaitErr
+func WithFailFast() Option {
+	return func(c *executorConfig) {
+		c.failFast = true
+	}
+}
+
+// WithPanicPolicy sets how panics raised by units of work are handled
+func WithPanicPolicy(policy PanicPolicy) Option {
+	return func(c *executorConfig) {
+		c.panicPolicy = policy
+	}
+}
+
+// WithPanicHandler sets the PanicHandle policy, calling the handler with each panic raised by a unit of work
+func WithPanicHandler(handler func(PanicError)) Option {
+	return func(c *executorConfig) {
+		c.panicPolicy = PanicHandle
+		c.panicHandler = handler
+	}
+}
+
+// WithTaskTimeout sets a timeout for units of work submitted with GoCtx, after which the context provided
+// is canceled. The timeout starts when the unit of work begins executing
+func WithTaskTimeout(timeout time.Duration) Option {
+	return func(c *executorConfig) {
+		c.taskTimeout = timeout
+	}
+}
+
+// WithPriorityAging raises the effective priority of units of work waiting in a PriorityExecutor by 1 for each
+// interval waited, so low priority work is not starved by a steady stream of higher priority work
+func WithPriorityAging(interval time.Duration) Option {
+	return func(c *executorConfig) {
+		c.priorityAging = interval
+	}
+}
+
+// WithDrainTimeout sets the maximum time executing units of work are waited on after Wait observes a canceled
+// context, and the maximum time Close waits for units of work to finish. By default, both wait until executing
+// units of work finish. With a negative timeout, Wait returns as soon as the context is canceled, while units of
+// work which already started may still be executing
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(c *executorConfig) {
+		c.drainTimeout = timeout
+	}
+}
+
+// WithRetainSkipped causes the functions not executed due to a canceled context or failing fast to be returned
+// in the WaitResult, so they can be persisted or resubmitted
+func WithRetainSkipped() Option {
+	return func(c *executorConfig) {
+		c.retainSkipped = true
+	}
+}
+
+// WithClock sets the Clock used for time based features: scheduling, rate limiting, priority aging, the latency
+// observed by adaptive executors and the drain timeout used by Wait, allowing time to be controlled in tests with a FakeClock
+func WithClock(clock Clock) Option {
+	return func(c *executorConfig) {
+		c.clock = clock
+	}
+}
+
+// getClock returns the configured Clock, or the system clock
+func (c *executorConfig) getClock() Clock {
+	if c.clock == nil {
+		return SystemClock()
+	}
+	return c.clock
+}
+
+// reject reports the error to the rejection handler, if one is configured
+func (c *executorConfig) reject(err error) {
+	if c.rejectionHandler != nil {
+		c.rejectionHandler(err)
+	}
+}
+
+// newChild returns a new executor based on the configured ChildStrategy, or nil when the parent should be reused
+func (c *executorConfig) newChild(maxConcurrency int) Executor {
+	switch c.childStrategy {
+	case ChildQueued:
+		return newQueuedExecutorWithConfig(maxConcurrency, *c)
+	case ChildSerial:
+		return newSerialExecutor(*c)
+	case ChildSelf:
+		return nil
+	default:
+		return newBoundedExecutorWithConfig(maxConcurrency, *c)
+	}
+}
@@ -0,0 +1,174 @@
+package sync
+
+import (
+	"container/heap"
+	"context"
+	"time"
+)
+
+// PriorityExecutor is an Executor which executes queued units of work with a higher priority first
+type PriorityExecutor interface {
+	Executor
+
+	// GoPriority adds a unit of work to be executed with the given priority, Go uses a priority of 0
+	GoPriority(priority int, fn func())
+}
+
+// priorityExecutor is a queuedExecutor using a priority queue
+type priorityExecutor struct {
+	*queuedExecutor
+}
+
+var _ interface {
+	PriorityExecutor
+	ErrorExecutor
+	ContextualExecutor
+	SkipExecutor
+	ChildExecutor
+	namedExecutor
+} = (*priorityExecutor)(nil)
+
+// NewPriorityExecutor returns an executor which queues units of work like NewQueuedExecutor, executing those with
+// a higher priority first and those with the same priority in the order they were submitted. Use WithPriorityAging
+// to prevent low priority units of work from waiting indefinitely. With the ChildBounded and ChildQueued
+// strategies, ChildExecutor returns a PriorityExecutor with the same concurrency and options, so nested units of
+// work are also prioritized
+func NewPriorityExecutor(maxConcurrency int, opts ...Option) PriorityExecutor {
+	return newPriorityExecutorWithConfig(maxConcurrency, newExecutorConfig(opts...))
+}
+
+func newPriorityExecutorWithConfig(maxConcurrency int, cfg executorConfig) *priorityExecutor {
+	e := newQueuedExecutorWithConfig(maxConcurrency, cfg)
+	clock := cfg.getClock()
+	e.queue = &priorityQueue{
+		clock: clock,
+		aging: cfg.priorityAging,
+		start: clock.Now(),
+	}
+	return &priorityExecutor{queuedExecutor: e}
+}
+
+func (e *priorityExecutor) GoPriority(priority int, fn func()) {
+	e.submit(priority, fn, nil)
+}
+
+func (e *priorityExecutor) ChildExecutor() Executor {
+	e.childLock.Lock()
+	if e.childExecutor == nil {
+		switch e.config.childStrategy {
+		case ChildBounded, ChildQueued:
+			e.childExecutor = newPriorityExecutorWithConfig(e.MaxConcurrency(), e.config)
+		}
+	}
+	e.childLock.Unlock()
+	child := e.queuedExecutor.ChildExecutor()
+	if child == e.queuedExecutor {
+		return e
+	}
+	return child
+}
+
+type priorityKey struct{}
+
+// SetContextPriority returns a context with the priority to be used by GoContextPriority
+func SetContextPriority(ctx context.Context, priority int) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// ContextPriority returns the priority set with SetContextPriority, or 0 if none is set
+func ContextPriority(ctx context.Context) int {
+	if ctx == nil {
+		return 0
+	}
+	priority, _ := ctx.Value(priorityKey{}).(int)
+	return priority
+}
+
+// GoContextPriority adds the unit of work to the executor with the priority in the context, if the executor is
+// a PriorityExecutor, otherwise calls Go
+func GoContextPriority(ctx context.Context, executor Executor, fn func()) {
+	if e, ok := executor.(PriorityExecutor); ok {
+		e.GoPriority(ContextPriority(ctx), fn)
+		return
+	}
+	executor.Go(fn)
+}
+
+// priorityQueue is a taskQueue ordered by priority, then by submission order. It is not safe for concurrent use,
+// queuedExecutor guards all calls with its own lock
+type priorityQueue struct {
+	clock Clock
+	aging time.Duration
+	start time.Time
+	seq   uint64
+	items priorityItems
+}
+
+var _ taskQueue = (*priorityQueue)(nil)
+
+func (q *priorityQueue) Enqueue(task *queuedTask) {
+	q.seq++
+	heap.Push(&q.items, priorityItem{
+		task:  task,
+		score: q.score(task.priority),
+		seq:   q.seq,
+	})
+}
+
+func (q *priorityQueue) Dequeue() (*queuedTask, bool) {
+	if len(q.items) == 0 {
+		return nil, false
+	}
+	return heap.Pop(&q.items).(priorityItem).task, true
+}
+
+func (q *priorityQueue) Len() int {
+	return len(q.items)
+}
+
+// score returns the value items are ordered by. With aging, an item's effective priority increases by 1 for every
+// aging interval it waits; since all waiting items age at the same rate, this is equivalent to lowering the
+// priority of items based on how late they were enqueued, so the ordering of waiting items never changes
+func (q *priorityQueue) score(priority int) float64 {
+	if q.aging <= 0 {
+		return float64(priority)
+	}
+	return float64(priority) - float64(q.clock.Now().Sub(q.start))/float64(q.aging)
+}
+
+type priorityItem struct {
+	task  *queuedTask
+	score float64
+	seq   uint64
+}
+
+// priorityItems implements heap.Interface, with the highest score first
+type priorityItems []priorityItem
+
+func (p priorityItems) Len() int {
+	return len(p)
+}
+
+func (p priorityItems) Less(i, j int) bool {
+	if p[i].score == p[j].score {
+		return p[i].seq < p[j].seq
+	}
+	return p[i].score > p[j].score
+}
+
+func (p priorityItems) Swap(i, j int) {
+	p[i], p[j] = p[j], p[i]
+}
+
+func (p *priorityItems) Push(x any) {
+	*p = append(*p, x.(priorityItem))
+}
+
+func (p *priorityItems) Pop() any {
+	old := *p
+	last := len(old) - 1
+	item := old[last]
+	old[last] = priorityItem{}
+	*p = old[:last]
+	return item
+}
@@ -0,0 +1,165 @@
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// blockPriorityExecutor submits a unit of work occupying the only slot of the executor, returning a function
+// to release it
+func blockPriorityExecutor(e Executor) (release func()) {
+	started := make(chan struct{})
+	done := make(chan struct{})
+	e.Go(func() {
+		close(started)
+		<-done
+	})
+	<-started
+	return func() {
+		close(done)
+	}
+}
+
+func Test_PriorityExecutor(t *testing.T) {
+	e := NewPriorityExecutor(1)
+	release := blockPriorityExecutor(e)
+
+	order := List[string]{}
+	e.GoPriority(0, func() {
+		order.Append("low 1")
+	})
+	e.GoPriority(10, func() {
+		order.Append("high 1")
+	})
+	e.Go(func() {
+		order.Append("low 2")
+	})
+	e.GoPriority(5, func() {
+		order.Append("medium")
+	})
+	e.GoPriority(10, func() {
+		order.Append("high 2")
+	})
+
+	release()
+	e.Wait(context.Background())
+
+	require.Equal(t, []string{"high 1", "high 2", "medium", "low 1", "low 2"}, order.Values())
+}
+
+func Test_PriorityExecutorAging(t *testing.T) {
+	tests := []struct {
+		name     string
+		aging    time.Duration
+		expected []string
+	}{
+		{
+			name:     "no aging",
+			expected: []string{"high", "low"},
+		},
+		{
+			name:     "aging",
+			aging:    time.Millisecond,
+			expected: []string{"low", "high"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewFakeClock(time.Now())
+			e := NewPriorityExecutor(1, WithPriorityAging(tt.aging), WithClock(clock))
+			release := blockPriorityExecutor(e)
+
+			order := List[string]{}
+			e.GoPriority(0, func() {
+				order.Append("low")
+			})
+			// with aging, the low priority work will have waited more than 1 priority level longer
+			clock.Advance(20 * time.Millisecond)
+			e.GoPriority(1, func() {
+				order.Append("high")
+			})
+
+			release()
+			e.Wait(context.Background())
+
+			require.Equal(t, tt.expected, order.Values())
+		})
+	}
+}
+
+func Test_GoContextPriority(t *testing.T) {
+	ctx := SetContextExecutor(context.Background(), "", NewPriorityExecutor(1))
+	e := ContextExecutor(&ctx, "")
+	release := blockPriorityExecutor(e)
+
+	order := List[string]{}
+	GoContextPriority(ctx, e, func() {
+		order.Append("default")
+	})
+	GoContextPriority(SetContextPriority(ctx, 1), e, func() {
+		order.Append("priority")
+	})
+
+	release()
+	e.Wait(context.Background())
+
+	require.Equal(t, []string{"priority", "default"}, order.Values())
+	require.Equal(t, 0, ContextPriority(ctx))
+	require.Equal(t, 1, ContextPriority(SetContextPriority(ctx, 1)))
+
+	// non-priority executors execute normally
+	executed := false
+	GoContextPriority(SetContextPriority(ctx, 1), NewExecutor(0), func() {
+		executed = true
+	})
+	require.True(t, executed)
+}
+
+func Test_PriorityExecutorSubcontext(t *testing.T) {
+	done := make(chan struct{})
+	ctx := SetContextExecutor(context.TODO(), "", NewPriorityExecutor(1, WithChildStrategy(ChildSelf)))
+	ContextExecutor(&ctx, "").Go(func() {
+		// the same executor is used, but work is queued rather than blocking
+		ContextExecutor(&ctx, "").Go(func() {
+			close(done)
+		})
+	})
+	<-done
+	require.IsType(t, &priorityExecutor{}, ContextExecutor(&ctx, ""))
+}
+
+func Test_PriorityExecutorChild(t *testing.T) {
+	for _, strategy := range []ChildStrategy{ChildBounded, ChildQueued} {
+		e := NewPriorityExecutor(1, WithChildStrategy(strategy))
+		child := e.(ChildExecutor).ChildExecutor()
+		require.IsType(t, &priorityExecutor{}, child)
+		require.NotSame(t, e, child)
+	}
+
+	// the default strategy prioritizes nested units of work
+	ctx := SetContextExecutor(context.TODO(), "", NewPriorityExecutor(1))
+	var order []int
+	var child Executor
+	done := make(chan struct{})
+	ContextExecutor(&ctx, "").Go(func() {
+		child = ContextExecutor(&ctx, "")
+		release := make(chan struct{})
+		child.Go(func() {
+			<-release
+		})
+		for i := range 3 {
+			GoContextPriority(SetContextPriority(ctx, i), child, func() {
+				order = append(order, i)
+			})
+		}
+		close(release)
+		child.Wait(context.Background())
+		close(done)
+	})
+	<-done
+	require.IsType(t, &priorityExecutor{}, child)
+	require.Equal(t, []int{2, 1, 0}, order)
+}
@@ -2,55 +2,170 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"sync"
-	"sync/atomic"
+	"time"
 )
 
 // queuedExecutor is an Executor that accepts units of work to execute asynchronously, queuing them rather than blocking
 type queuedExecutor struct {
-	canceled       atomic.Bool
+	config         executorConfig
+	life           lifecycle
 	maxConcurrency int
-	executing      atomic.Int32
-	queue          List[*func()]
-	wg             sync.WaitGroup
+	lock           sync.Mutex
+	space          sync.Cond
+	executing      int
+	queue          taskQueue
 	childLock      sync.RWMutex
-	childExecutor  *errGroupExecutor
+	childExecutor  Executor
+	completed      func(runTime time.Duration)
+	errs           taskErrors
+	tasks          taskContexts
+	stats          taskStats
+	taskLabels
 }
 
-var _ Executor = (*queuedExecutor)(nil)
+var _ interface {
+	ErrorExecutor
+	ContextualExecutor
+	SkipExecutor
+	ChildExecutor
+	Resizable
+	ExecutorStats
+	ShutdownExecutor
+	ResultExecutor
+	namedExecutor
+} = (*queuedExecutor)(nil)
+
+// NewQueuedExecutor returns an Executor which queues units of work, executing up to maxConcurrency at a time.
+// Unlike the executor returned from NewExecutor, Go does not block the caller unless a queue capacity is set
+// along with the RejectBlock policy. A maxConcurrency < 1 is treated as 1
+func NewQueuedExecutor(maxConcurrency int, opts ...Option) Executor {
+	return newQueuedExecutorWithConfig(maxConcurrency, newExecutorConfig(opts...))
+}
+
+func newQueuedExecutorWithConfig(maxConcurrency int, config executorConfig) *queuedExecutor {
+	e := &queuedExecutor{
+		config:         config,
+		maxConcurrency: max(maxConcurrency, 1),
+		life:           newLifecycle(config),
+		errs:           newTaskErrors(config),
+		tasks:          newTaskContexts(config),
+		stats:          newTaskStats(config),
+	}
+	e.life.drain = e.drain
+	return e
+}
+
+// queuedTask is a unit of work waiting to be executed by a queuedExecutor
+type queuedTask struct {
+	fn       func()
+	priority int
+	group    *taskGroup
+}
+
+// taskQueue orders the units of work waiting to be executed by a queuedExecutor
+type taskQueue interface {
+	Queue[*queuedTask]
+	Len() int
+}
 
 func (e *queuedExecutor) Go(f func()) {
-	if e.canceled.Load() {
+	e.submit(0, f, nil)
+}
+
+func (e *queuedExecutor) GoSkip(f func(), skipped func(error)) {
+	e.submit(0, f, skipped)
+}
+
+// submit queues the function with the provided priority, which is only used by prioritized queues, calling
+// skipped if it will not be executed
+func (e *queuedExecutor) submit(priority int, f func(), skipped func(error)) {
+	group, id, ok := e.life.add(f)
+	if !ok {
+		rejectClosed(&e.config, &e.errs, &e.stats, skipped)
 		return
 	}
-	e.wg.Add(1)
+	submitted := e.stats.submitted()
 	fn := func() {
-		defer e.wg.Done()
-		if e.canceled.Load() {
+		defer group.wg.Done()
+		if !group.start(id, e.errs.skip()) {
+			e.stats.skipped()
+			skipTask(skipped, ErrSkipped)
 			return
 		}
-		f()
+		defer group.finish()
+		defer e.stats.started(submitted)()
+		if e.completed != nil {
+			start := e.life.clock.Now()
+			defer func() {
+				e.completed(e.life.clock.Now().Sub(start))
+			}()
+		}
+		e.errs.call(e.wrap(f))
 	}
-	e.queue.Enqueue(&fn)
-	if int(e.executing.Load()) < e.maxConcurrency {
-		go e.exec()
+	accepted, err := e.enqueue(&queuedTask{fn: fn, priority: priority, group: group})
+	if err != nil {
+		e.errs.record(err)
+		e.config.reject(err)
+	}
+	if !accepted {
+		group.remove(id)
+		group.wg.Done()
+		e.stats.reject()
+		// dropped units of work are still reported to the submitter, so it does not wait for them
+		skipTask(skipped, ErrQueueFull)
 	}
 }
 
+func (e *queuedExecutor) GoErr(f func() error) {
+	e.Go(func() {
+		e.errs.run(f)
+	})
+}
+
+func (e *queuedExecutor) GoCtx(f func(context.Context)) {
+	ctx := e.tasks.context()
+	e.Go(func() {
+		e.tasks.run(ctx, f)
+	})
+}
+
 func (e *queuedExecutor) Wait(ctx context.Context) {
-	e.canceled.Store(ctx.Err() != nil)
+	e.WaitResult(ctx)
+}
 
-	done := make(chan struct{})
-	go func() {
-		e.wg.Wait()
-		close(done)
-	}()
+// WaitResult waits like Wait, when the context is canceled the queued units of work are removed from the queue
+// and reported as skipped
+func (e *queuedExecutor) WaitResult(ctx context.Context) WaitResult {
+	result, _ := e.life.wait(ctx, &e.tasks)
+	return result
+}
 
-	select {
-	case <-ctx.Done():
-		e.canceled.Store(true)
-	case <-done:
-	}
+func (e *queuedExecutor) WaitErr(ctx context.Context) error {
+	e.Wait(ctx)
+	return e.errs.take()
+}
+
+// Shutdown shuts down the executor and then the child executor, if one was created. Queued units of work are
+// executed unless the context is canceled first
+func (e *queuedExecutor) Shutdown(ctx context.Context) error {
+	err := errors.Join(e.life.shutdown(ctx, &e.tasks), e.errs.take())
+	return errors.Join(err, shutdownChild(ctx, e, &e.childLock, &e.childExecutor))
+}
+
+func (e *queuedExecutor) Close() error {
+	ctx, cancel := e.life.closeContext()
+	defer cancel()
+	return e.Shutdown(ctx)
+}
+
+func (e *queuedExecutor) State() ExecutorState {
+	return e.life.State()
+}
+
+func (e *queuedExecutor) Stats() Stats {
+	return e.stats.snapshot(&e.errs)
 }
 
 func (e *queuedExecutor) ChildExecutor() Executor {
@@ -64,24 +179,138 @@ func (e *queuedExecutor) ChildExecutor() Executor {
 	defer e.childLock.Unlock()
 	if e.childExecutor == nil {
 		// create child executor with same bound
-		e.childExecutor = newErrGroupExecutor(e.maxConcurrency)
+		e.childExecutor = e.config.newChild(e.MaxConcurrency())
+		if e.childExecutor == nil {
+			e.childExecutor = e
+		}
 	}
 	return e.childExecutor
 }
 
-func (e *queuedExecutor) exec() {
-	e.executing.Add(1)
-	defer e.executing.Add(-1)
-	if int(e.executing.Load()) > e.maxConcurrency {
+func (e *queuedExecutor) MaxConcurrency() int {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return e.maxConcurrency
+}
+
+func (e *queuedExecutor) SetMaxConcurrency(maxConcurrency int) {
+	e.lock.Lock()
+	e.maxConcurrency = max(maxConcurrency, 1)
+	// when reduced, executing goroutines exit as they finish their current unit of work
+	e.startWorkers()
+	e.lock.Unlock()
+
+	e.childLock.RLock()
+	defer e.childLock.RUnlock()
+	if child, ok := e.childExecutor.(Resizable); ok && e.childExecutor != e {
+		child.SetMaxConcurrency(maxConcurrency)
+	}
+}
+
+// queued returns the number of units of work waiting to execute
+func (e *queuedExecutor) queued() int {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.queue == nil {
+		return 0
+	}
+	return e.queue.Len()
+}
+
+// enqueue adds the function to the queue, applying the rejection policy when the queue is full, and starts
+// a new goroutine to process the queue if fewer than maxConcurrency are executing. Returns false when the
+// unit of work was not accepted
+func (e *queuedExecutor) enqueue(task *queuedTask) (bool, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.queue == nil {
+		e.queue = &List[*queuedTask]{}
+	}
+	for e.config.queueCapacity > 0 && e.queue.Len() >= e.config.queueCapacity {
+		switch e.config.rejection {
+		case RejectDrop:
+			return false, nil
+		case RejectError:
+			return false, ErrQueueFull
+		default:
+			if e.space.L == nil {
+				e.space.L = &e.lock
+			}
+			e.space.Wait()
+		}
+	}
+	e.queue.Enqueue(task)
+	e.startWorkers()
+	return true, nil
+}
+
+// startWorkers starts goroutines to process the queue, up to maxConcurrency, must be called with the lock held
+func (e *queuedExecutor) startWorkers() {
+	if e.queue == nil {
 		return
 	}
+	// each goroutine continues processing until the queue is empty, so only start one per queued unit of work
+	for i := 0; i < e.queue.Len() && e.executing < e.maxConcurrency; i++ {
+		e.executing++
+		go e.exec()
+	}
+}
+
+// dequeue returns the next function to execute, or false when the queue is empty and this goroutine should exit
+func (e *queuedExecutor) dequeue() (*queuedTask, bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.executing > e.maxConcurrency {
+		// the maximum concurrency was reduced
+		e.executing--
+		return nil, false
+	}
+	task, ok := e.queue.Dequeue()
+	if !ok {
+		e.executing--
+		return nil, false
+	}
+	if e.space.L != nil {
+		e.space.Signal()
+	}
+	return task, true
+}
+
+// drain removes the units of work belonging to canceled groups from the queue, skipping them immediately rather
+// than when dequeued
+func (e *queuedExecutor) drain() {
+	e.lock.Lock()
+	var skipped, kept []*queuedTask
+	if e.queue != nil {
+		for task, ok := e.queue.Dequeue(); ok; task, ok = e.queue.Dequeue() {
+			if task.group.isCanceled() {
+				skipped = append(skipped, task)
+			} else {
+				kept = append(kept, task)
+			}
+		}
+		for _, task := range kept {
+			e.queue.Enqueue(task)
+		}
+	}
+	if e.space.L != nil {
+		e.space.Broadcast()
+	}
+	e.lock.Unlock()
+
+	for _, task := range skipped {
+		task.fn()
+	}
+}
+
+func (e *queuedExecutor) exec() {
 	for {
-		f, ok := e.queue.Dequeue()
+		task, ok := e.dequeue()
 		if !ok {
 			return
 		}
-		if f != nil {
-			(*f)()
+		if task != nil {
+			task.fn()
 		}
 	}
 }
@@ -170,6 +170,7 @@ func Test_queuedExecutorCancel(t *testing.T) {
 	}
 
 	executed := [3]bool{}
+	submitted := make(chan struct{})
 	e.Go(func() {
 		wns[0].Done()
 		wgs[0].Wait()
@@ -188,26 +189,34 @@ func Test_queuedExecutorCancel(t *testing.T) {
 		// 0 and 1 are currently executing, waiting
 		cancel()
 
+		// the canceled Wait waits for 0 and 1 to finish executing
+		wgs[0].Done()
+		wgs[1].Done()
+
 		wns[2].Wait()
 
 		e.Go(func() {
 			wgs[2].Wait()
 			executed[2] = true
 		})
-
-		for i := range wgs {
-			wgs[i].Done()
-		}
-
+		wgs[2].Done()
+		close(submitted)
 	}()
 
 	// should be waiting in 0, 1 not executed 2
 	e.Wait(ctx)
 
+	// should have waited for 0 and 1, and not executed 2
+	require.True(t, executed[0])
+	require.True(t, executed[1])
+	require.False(t, executed[2])
+
 	wns[2].Done()
 
-	// should not have executed 2
-	require.False(t, executed[2])
+	// units of work submitted after a canceled Wait are executed
+	<-submitted
+	e.Wait(context.Background())
+	require.True(t, executed[2])
 }
 
 func Test_queuedExecutorSubcontext(t *testing.T) {
@@ -226,3 +235,165 @@ func Test_queuedExecutorSubcontext(t *testing.T) {
 	})
 	wg.Wait() // only done by sub-executor
 }
+
+func Test_NewQueuedExecutorDoesNotBlock(t *testing.T) {
+	e := NewQueuedExecutor(1)
+
+	release := make(chan struct{})
+	executed := atomic.Uint64{}
+	for range 10 {
+		// none of these should block, even though only 1 can execute at a time
+		e.Go(func() {
+			<-release
+			executed.Add(1)
+		})
+	}
+	close(release)
+
+	e.Wait(context.Background())
+	require.Equal(t, uint64(10), executed.Load())
+}
+
+func Test_NewQueuedExecutorRejection(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   RejectionPolicy
+		executed uint64
+		rejected []error
+	}{
+		{
+			name:     "drop",
+			policy:   RejectDrop,
+			executed: 2,
+		},
+		{
+			name:     "error",
+			policy:   RejectError,
+			executed: 2,
+			rejected: []error{ErrQueueFull, ErrQueueFull},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rejected []error
+			e := NewQueuedExecutor(1,
+				WithQueueCapacity(1),
+				WithRejectionPolicy(tt.policy),
+				WithRejectionHandler(func(err error) {
+					rejected = append(rejected, err)
+				}),
+			)
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+			executed := atomic.Uint64{}
+
+			e.Go(func() {
+				close(started)
+				<-release
+				executed.Add(1)
+			})
+			<-started
+
+			// first fills the queue, the remaining are rejected
+			var skipped []error
+			for range 3 {
+				e.(SkipExecutor).GoSkip(func() {
+					executed.Add(1)
+				}, func(err error) {
+					skipped = append(skipped, err)
+				})
+			}
+			close(release)
+
+			err := e.(ErrorExecutor).WaitErr(context.Background())
+			require.Equal(t, tt.executed, executed.Load())
+			require.Equal(t, tt.rejected, rejected)
+			// submitters are notified for all policies
+			require.Equal(t, []error{ErrQueueFull, ErrQueueFull}, skipped)
+			if tt.rejected != nil {
+				require.ErrorIs(t, err, ErrQueueFull)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_NewQueuedExecutorBlocksWhenFull(t *testing.T) {
+	e := NewQueuedExecutor(1, WithQueueCapacity(1), WithRejectionPolicy(RejectBlock))
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	executed := atomic.Uint64{}
+
+	e.Go(func() {
+		close(started)
+		<-release
+		executed.Add(1)
+	})
+	<-started
+
+	// fills the queue
+	e.Go(func() {
+		executed.Add(1)
+	})
+
+	submitted := make(chan struct{})
+	go func() {
+		defer close(submitted)
+		e.Go(func() {
+			executed.Add(1)
+		})
+	}()
+
+	select {
+	case <-submitted:
+		require.Fail(t, "Go should block while the queue is full")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(release)
+	<-submitted
+
+	e.Wait(context.Background())
+	require.Equal(t, uint64(3), executed.Load())
+}
+
+func Test_NewQueuedExecutorChildStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy ChildStrategy
+		expected Executor
+	}{
+		{
+			name:     "bounded",
+			strategy: ChildBounded,
+			expected: &boundedExecutor{},
+		},
+		{
+			name:     "queued",
+			strategy: ChildQueued,
+			expected: &queuedExecutor{},
+		},
+		{
+			name:     "serial",
+			strategy: ChildSerial,
+			expected: &serialExecutor{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewQueuedExecutor(2, WithChildStrategy(tt.strategy))
+			child := e.(ChildExecutor).ChildExecutor()
+			require.IsType(t, tt.expected, child)
+			// the same child should be returned each time
+			require.Equal(t, child, e.(ChildExecutor).ChildExecutor())
+		})
+	}
+
+	t.Run("self", func(t *testing.T) {
+		e := NewQueuedExecutor(2, WithChildStrategy(ChildSelf))
+		require.Same(t, e, e.(ChildExecutor).ChildExecutor())
+	})
+}
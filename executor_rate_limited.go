@@ -0,0 +1,218 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// RateLimitedExecutor is an Executor which limits how often units of work are started
+type RateLimitedExecutor interface {
+	Executor
+
+	// RateLimiter returns the RateLimiter used, which provides statistics about throttling
+	RateLimiter() *RateLimiter
+}
+
+// rateLimitedExecutor is an Executor which waits for a RateLimiter token before passing each unit of work
+// to another executor
+type rateLimitedExecutor struct {
+	executor      Executor
+	limiter       *RateLimiter
+	life          lifecycle
+	errs          taskErrors
+	tasks         taskContexts
+	stats         taskStats
+	childLock     sync.RWMutex
+	childExecutor Executor
+	taskLabels
+}
+
+var _ interface {
+	RateLimitedExecutor
+	ErrorExecutor
+	ContextualExecutor
+	SkipExecutor
+	ChildExecutor
+	ExecutorStats
+	ShutdownExecutor
+	ResultExecutor
+	namedExecutor
+} = (*rateLimitedExecutor)(nil)
+
+// RateLimit returns an Executor which waits for a token from the limiter before passing each unit of work to the
+// provided executor. Go blocks while waiting for a token, and if Wait observes a canceled context, units of work
+// waiting for a token are discarded
+func RateLimit(executor Executor, limiter *RateLimiter) RateLimitedExecutor {
+	return &rateLimitedExecutor{
+		executor: executor,
+		limiter:  limiter,
+	}
+}
+
+// NewRateLimitedExecutor returns an executor with the given concurrency, like NewExecutor, which starts at most
+// perSecond units of work per second on average, with bursts of up to burst units of work
+func NewRateLimitedExecutor(maxConcurrency int, perSecond float64, burst int, opts ...Option) RateLimitedExecutor {
+	cfg := newExecutorConfig(opts...)
+	return &rateLimitedExecutor{
+		executor: NewExecutor(maxConcurrency, opts...),
+		limiter:  NewRateLimiter(perSecond, burst, opts...),
+		life:     newLifecycle(cfg),
+		errs:     newTaskErrors(cfg),
+		tasks:    newTaskContexts(cfg),
+		stats:    newTaskStats(cfg),
+	}
+}
+
+func (e *rateLimitedExecutor) RateLimiter() *RateLimiter {
+	return e.limiter
+}
+
+func (e *rateLimitedExecutor) Go(f func()) {
+	e.GoSkip(f, nil)
+}
+
+// GoSkip adds the unit of work like Go, calling skipped with ErrSkipped if it is discarded while waiting for a
+// token or failing fast due to an error from GoErr, or the reason reported by the underlying executor, if it is a
+// SkipExecutor
+func (e *rateLimitedExecutor) GoSkip(f func(), skipped func(error)) {
+	// once shut down, the underlying executor rejects units of work without waiting for a token
+	if e.life.accepting() {
+		if e.errs.skip() {
+			e.stats.dropped()
+			skipTask(skipped, ErrSkipped)
+			return
+		}
+		if err := e.limiter.Wait(e.tasks.context()); err != nil {
+			e.stats.dropped()
+			skipTask(skipped, ErrSkipped)
+			return
+		}
+	}
+	goSkip(e.executor, e.wrap(func() {
+		// errors from GoErr are recorded by this executor, so the underlying executor does not fail fast for them
+		if e.errs.skip() {
+			e.stats.dropped()
+			skipTask(skipped, ErrSkipped)
+			return
+		}
+		f()
+	}), skipped)
+}
+
+func (e *rateLimitedExecutor) GoErr(f func() error) {
+	e.Go(func() {
+		e.errs.run(f)
+	})
+}
+
+func (e *rateLimitedExecutor) GoCtx(f func(context.Context)) {
+	ctx := e.tasks.context()
+	e.Go(func() {
+		e.tasks.run(ctx, f)
+	})
+}
+
+func (e *rateLimitedExecutor) Wait(ctx context.Context) {
+	canceled := e.tasks.wait(ctx)
+	e.executor.Wait(ctx)
+	if ctx.Err() != nil {
+		canceled()
+		e.tasks.reset()
+	}
+}
+
+// WaitResult waits like Wait, returning the result from the underlying executor if it is a ResultExecutor. Units
+// of work discarded while waiting for a token are not included
+func (e *rateLimitedExecutor) WaitResult(ctx context.Context) WaitResult {
+	executor, ok := e.executor.(ResultExecutor)
+	if !ok {
+		e.Wait(ctx)
+		return WaitResult{}
+	}
+	canceled := e.tasks.wait(ctx)
+	result := executor.WaitResult(ctx)
+	if ctx.Err() != nil {
+		canceled()
+		e.tasks.reset()
+	}
+	return result
+}
+
+// WaitErr waits like Wait, returning errors from units of work submitted with GoErr along with the errors
+// reported by the underlying executor, if it is an ErrorExecutor
+func (e *rateLimitedExecutor) WaitErr(ctx context.Context) error {
+	executor, ok := e.executor.(ErrorExecutor)
+	if !ok {
+		e.Wait(ctx)
+		return e.errs.take()
+	}
+	canceled := e.tasks.wait(ctx)
+	err := executor.WaitErr(ctx)
+	if ctx.Err() != nil {
+		canceled()
+		e.tasks.reset()
+	}
+	return errors.Join(err, e.errs.take())
+}
+
+// Shutdown shuts down the underlying executor, or waits for it if it is not a ShutdownExecutor. Units of work
+// waiting for a token are discarded if the context is canceled first
+func (e *rateLimitedExecutor) Shutdown(ctx context.Context) error {
+	e.life.setState(ExecutorDraining)
+	defer e.life.setState(ExecutorClosed)
+	canceled := e.tasks.wait(ctx)
+	var err error
+	if executor, ok := e.executor.(ShutdownExecutor); ok {
+		err = executor.Shutdown(ctx)
+	} else {
+		e.executor.Wait(ctx)
+		err = ctx.Err()
+	}
+	if ctx.Err() != nil {
+		canceled()
+	}
+	return errors.Join(err, e.errs.take())
+}
+
+func (e *rateLimitedExecutor) Close() error {
+	ctx, cancel := e.life.closeContext()
+	defer cancel()
+	return e.Shutdown(ctx)
+}
+
+func (e *rateLimitedExecutor) State() ExecutorState {
+	return e.life.State()
+}
+
+// Stats returns the Stats of the underlying executor, if available, including units of work discarded while
+// waiting for a token and panics from units of work submitted with GoErr
+func (e *rateLimitedExecutor) Stats() Stats {
+	var s Stats
+	if executor, ok := e.executor.(ExecutorStats); ok {
+		s = executor.Stats()
+	}
+	s.Canceled += e.stats.canceled.Load()
+	s.Panicked += e.errs.panics.Load()
+	return s
+}
+
+func (e *rateLimitedExecutor) ChildExecutor() Executor {
+	parent, ok := e.executor.(ChildExecutor)
+	if !ok {
+		return e
+	}
+	e.childLock.RLock()
+	child := e.childExecutor
+	e.childLock.RUnlock()
+	if child != nil {
+		return child
+	}
+	e.childLock.Lock()
+	defer e.childLock.Unlock()
+	if e.childExecutor == nil {
+		// nested units of work share the same rate limit
+		e.childExecutor = RateLimit(parent.ChildExecutor(), e.limiter)
+	}
+	return e.childExecutor
+}
@@ -0,0 +1,81 @@
+package sync
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RateLimitedExecutor(t *testing.T) {
+	e := NewRateLimitedExecutor(-1, 200, 2)
+
+	executed := atomic.Int32{}
+	start := time.Now()
+	for range 6 {
+		e.Go(func() {
+			executed.Add(1)
+		})
+	}
+	e.Wait(context.Background())
+
+	require.Equal(t, int32(6), executed.Load())
+	// 2 are allowed by the burst, the remaining 4 at 5ms intervals
+	require.GreaterOrEqual(t, time.Since(start), 15*time.Millisecond)
+	require.GreaterOrEqual(t, e.RateLimiter().Stats().Throttled, int64(3))
+}
+
+func Test_RateLimitedExecutorCancel(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	e := RateLimit(NewExecutor(0), NewRateLimiter(0.001, 1, WithClock(clock)))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	executed := atomic.Int32{}
+	e.Go(func() {
+		executed.Add(1)
+	})
+
+	submitted := make(chan struct{})
+	go func() {
+		defer close(submitted)
+		// waits for a token until Wait is canceled
+		e.Go(func() {
+			executed.Add(1)
+		})
+	}()
+
+	// units of work submitted after Wait returns are not discarded, so only cancel once waiting for a token
+	require.NoError(t, clock.BlockUntil(context.Background(), 1))
+	cancel()
+	e.Wait(ctx)
+	<-submitted
+	require.Equal(t, int32(1), executed.Load())
+}
+
+func Test_RateLimitedExecutorSubcontext(t *testing.T) {
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	ctx := SetContextExecutor(context.TODO(), "", NewRateLimitedExecutor(1, 1000, 1))
+	ContextExecutor(&ctx, "").Go(func() {
+		// context should be replaced with a secondary executor
+		ContextExecutor(&ctx, "").Go(func() {
+			wg.Done()
+		})
+	})
+	wg.Wait() // only done by sub-executor
+}
+
+func Test_RateLimitedExecutorCapturesPanics(t *testing.T) {
+	e := NewRateLimitedExecutor(2, 1000, 10, WithPanicPolicy(PanicCapture))
+	e.Go(func() {
+		panic("captured")
+	})
+
+	// panics captured by the underlying executor are reported
+	var p PanicError
+	require.ErrorAs(t, e.(ErrorExecutor).WaitErr(context.Background()), &p)
+	require.Equal(t, "captured", p.Value)
+}
@@ -3,13 +3,99 @@ package sync
 import "context"
 
 // serialExecutor is an Executor that executes serially, without any goroutines
-type serialExecutor struct{}
+type serialExecutor struct {
+	config executorConfig
+	life   lifecycle
+	errs   taskErrors
+	tasks  taskContexts
+	stats  taskStats
+	taskLabels
+}
+
+func newSerialExecutor(config executorConfig) *serialExecutor {
+	return &serialExecutor{
+		config: config,
+		life:   newLifecycle(config),
+		errs:   newTaskErrors(config),
+		tasks:  newTaskContexts(config),
+		stats:  newTaskStats(config),
+	}
+}
+
+func (u *serialExecutor) Go(fn func()) {
+	u.GoSkip(fn, nil)
+}
+
+func (u *serialExecutor) GoSkip(fn func(), skipped func(error)) {
+	group, id, ok := u.life.add(fn)
+	if !ok {
+		rejectClosed(&u.config, &u.errs, &u.stats, skipped)
+		return
+	}
+	defer group.wg.Done()
+	submitted := u.stats.submitted()
+	if !group.start(id, u.errs.skip()) {
+		u.stats.skipped()
+		skipTask(skipped, ErrSkipped)
+		return
+	}
+	defer group.finish()
+	defer u.stats.started(submitted)()
+	u.errs.call(u.wrap(fn))
+}
+
+func (u *serialExecutor) GoErr(fn func() error) {
+	u.Go(func() {
+		u.errs.run(fn)
+	})
+}
+
+func (u *serialExecutor) GoCtx(f func(context.Context)) {
+	ctx := u.tasks.context()
+	u.Go(func() {
+		u.tasks.run(ctx, f)
+	})
+}
+
+func (u *serialExecutor) Wait(ctx context.Context) {
+	u.WaitResult(ctx)
+}
+
+// WaitResult returns the outcome of units of work, which have already executed when Go returns
+func (u *serialExecutor) WaitResult(ctx context.Context) WaitResult {
+	result, _ := u.life.wait(ctx, &u.tasks)
+	return result
+}
+
+func (u *serialExecutor) WaitErr(ctx context.Context) error {
+	u.Wait(ctx)
+	return u.errs.take()
+}
+
+// Shutdown rejects subsequent units of work, functions have already executed when Go returns
+func (u *serialExecutor) Shutdown(_ context.Context) error {
+	u.life.setState(ExecutorClosed)
+	return u.errs.take()
+}
+
+func (u *serialExecutor) Close() error {
+	return u.Shutdown(context.Background())
+}
 
-func (u serialExecutor) Go(fn func()) {
-	fn()
+func (u *serialExecutor) State() ExecutorState {
+	return u.life.State()
 }
 
-func (u serialExecutor) Wait(_ context.Context) {
+func (u *serialExecutor) Stats() Stats {
+	return u.stats.snapshot(&u.errs)
 }
 
-var _ Executor = (*serialExecutor)(nil)
+var _ interface {
+	ErrorExecutor
+	ContextualExecutor
+	SkipExecutor
+	ExecutorStats
+	ShutdownExecutor
+	ResultExecutor
+	namedExecutor
+} = (*serialExecutor)(nil)
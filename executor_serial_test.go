@@ -10,7 +10,7 @@ func Test_serialSubcontext(t *testing.T) {
 	wg := sync.WaitGroup{}
 	wg.Add(1)
 	ctx := context.TODO()
-	ctx = SetContextExecutor(ctx, "", serialExecutor{})
+	ctx = SetContextExecutor(ctx, "", &serialExecutor{})
 	ContextExecutor(&ctx, "").Go(func() {
 		// context should be able to continue
 		ContextExecutor(&ctx, "").Go(func() {
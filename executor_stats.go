@@ -0,0 +1,199 @@
+package sync
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/anchore/go-sync/internal/stats"
+)
+
+// ExecutorStats is implemented by executors which provide statistics about the units of work submitted
+type ExecutorStats interface {
+	// Stats returns a snapshot of the current statistics
+	Stats() Stats
+}
+
+// Stats is a snapshot of the statistics of an executor
+type Stats struct {
+	// Queued is the number of units of work submitted and waiting to execute
+	Queued int64
+
+	// Active is the number of units of work currently executing
+	Active int64
+
+	// PeakActive is the highest number of units of work executing at once
+	PeakActive int64
+
+	// Completed is the number of units of work which finished executing, including those which panicked
+	Completed int64
+
+	// Canceled is the number of units of work which were not executed due to cancellation or failing fast
+	Canceled int64
+
+	// Rejected is the number of units of work which were not accepted, such as when a queue is full
+	Rejected int64
+
+	// Panicked is the number of units of work which panicked
+	Panicked int64
+
+	// WaitTime is the time units of work waited between being submitted and starting to execute
+	WaitTime Histogram
+
+	// RunTime is the time units of work spent executing
+	RunTime Histogram
+}
+
+// Histogram is a snapshot of durations counted in exponentially sized buckets
+type Histogram struct {
+	// Count is the number of durations observed
+	Count int64
+
+	// Sum is the total of all durations observed
+	Sum time.Duration
+
+	// Max is the largest duration observed
+	Max time.Duration
+
+	// Buckets are the non-empty buckets, ordered by UpperBound
+	Buckets []HistogramBucket
+}
+
+// HistogramBucket is the count of durations observed which are larger than the previous bucket's UpperBound
+// and at most UpperBound
+type HistogramBucket struct {
+	UpperBound time.Duration
+	Count      int64
+}
+
+// Mean returns the average duration observed
+func (h Histogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Count)
+}
+
+// Quantile returns an estimate of the duration at the quantile, between 0 and 1, which is the upper bound of the
+// bucket containing it, limited to Max
+func (h Histogram) Quantile(q float64) time.Duration {
+	target := int64(q * float64(h.Count))
+	var seen int64
+	for _, b := range h.Buckets {
+		seen += b.Count
+		if seen > target || seen == h.Count {
+			return min(b.UpperBound, h.Max)
+		}
+	}
+	return 0
+}
+
+// ContextExecutorStats returns the Stats of each executor registered with SetContextExecutor which implements
+// ExecutorStats, by name. Units of work submitted to child executors are not included
+func ContextExecutorStats(ctx context.Context) map[string]Stats {
+	out := map[string]Stats{}
+	if ctx == nil {
+		return out
+	}
+	executors, _ := ctx.Value(executorRegistryKey{}).(executorRegistry)
+	for name, executor := range executors {
+		if s, ok := executor.(ExecutorStats); ok {
+			out[name] = s.Stats()
+		}
+	}
+	return out
+}
+
+// taskStats records statistics about units of work, used by executors to implement ExecutorStats
+type taskStats struct {
+	clock     Clock
+	queued    stats.Tracked[int64]
+	active    stats.Tracked[int64]
+	completed atomic.Int64
+	canceled  atomic.Int64
+	rejected  atomic.Int64
+	waitTime  stats.Histogram
+	runTime   stats.Histogram
+}
+
+func newTaskStats(config executorConfig) taskStats {
+	return taskStats{
+		clock: config.getClock(),
+	}
+}
+
+// now returns the current time from the clock, or the system time if created without a config
+func (s *taskStats) now() time.Time {
+	if s.clock == nil {
+		return time.Now()
+	}
+	return s.clock.Now()
+}
+
+// submitted records a unit of work waiting to execute, returning the time it was submitted
+func (s *taskStats) submitted() time.Time {
+	s.queued.Incr()
+	return s.now()
+}
+
+// skipped records a submitted unit of work which will not execute due to cancellation
+func (s *taskStats) skipped() {
+	s.queued.Decr()
+	s.canceled.Add(1)
+}
+
+// dropped records a unit of work which was discarded before being submitted due to cancellation
+func (s *taskStats) dropped() {
+	s.canceled.Add(1)
+}
+
+// reject records a submitted unit of work which was not accepted
+func (s *taskStats) reject() {
+	s.queued.Decr()
+	s.rejected.Add(1)
+}
+
+// started records a submitted unit of work beginning to execute, returning a function to call when it finishes
+func (s *taskStats) started(submitted time.Time) (finished func()) {
+	start := s.now()
+	s.queued.Decr()
+	s.active.Incr()
+	s.waitTime.Observe(start.Sub(submitted))
+	return func() {
+		s.runTime.Observe(s.now().Sub(start))
+		s.active.Decr()
+		s.completed.Add(1)
+	}
+}
+
+// snapshot returns the current Stats, panics are counted by the executor's taskErrors
+func (s *taskStats) snapshot(errs *taskErrors) Stats {
+	return Stats{
+		Queued:     s.queued.Val(),
+		Active:     s.active.Val(),
+		PeakActive: s.active.Max(),
+		Completed:  s.completed.Load(),
+		Canceled:   s.canceled.Load(),
+		Rejected:   s.rejected.Load(),
+		Panicked:   errs.panics.Load(),
+		WaitTime:   histogramSnapshot(&s.waitTime),
+		RunTime:    histogramSnapshot(&s.runTime),
+	}
+}
+
+func histogramSnapshot(h *stats.Histogram) Histogram {
+	out := Histogram{
+		Count: h.Count(),
+		Sum:   h.Sum(),
+		Max:   h.Max(),
+	}
+	for i := range stats.HistogramBuckets {
+		if count := h.Bucket(i); count > 0 {
+			out.Buckets = append(out.Buckets, HistogramBucket{
+				UpperBound: stats.BucketBound(i),
+				Count:      count,
+			})
+		}
+	}
+	return out
+}
@@ -0,0 +1,202 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// StepExecutor is an Executor which queues units of work without executing them, so tests can execute them one
+// at a time, in the order they were submitted, from the test's goroutine
+type StepExecutor interface {
+	Executor
+
+	// Step executes the next queued unit of work in the calling goroutine, returning false if none are queued
+	Step() bool
+
+	// Pending returns the number of queued units of work
+	Pending() int
+}
+
+// stepExecutor is a StepExecutor, executing units of work only when stepped or waited on
+type stepExecutor struct {
+	config        executorConfig
+	life          lifecycle
+	lock          sync.Mutex
+	queue         List[*queuedTask]
+	childLock     sync.RWMutex
+	childExecutor Executor
+	errs          taskErrors
+	tasks         taskContexts
+	stats         taskStats
+	taskLabels
+}
+
+var _ interface {
+	StepExecutor
+	ErrorExecutor
+	ContextualExecutor
+	SkipExecutor
+	ChildExecutor
+	ExecutorStats
+	ShutdownExecutor
+	ResultExecutor
+	namedExecutor
+} = (*stepExecutor)(nil)
+
+// NewStepExecutor returns an executor for deterministic tests, which never starts goroutines: Go queues units of
+// work, which are executed by calls to Step, or by Wait executing all queued units of work in the calling
+// goroutine, including those queued while waiting. Child executors execute serially
+func NewStepExecutor(opts ...Option) StepExecutor {
+	e := &stepExecutor{
+		config: newExecutorConfig(opts...),
+	}
+	e.life = newLifecycle(e.config)
+	e.errs = newTaskErrors(e.config)
+	e.tasks = newTaskContexts(e.config)
+	e.stats = newTaskStats(e.config)
+	e.life.drain = e.drain
+	return e
+}
+
+func (e *stepExecutor) Go(f func()) {
+	e.GoSkip(f, nil)
+}
+
+func (e *stepExecutor) GoSkip(f func(), skipped func(error)) {
+	group, id, ok := e.life.add(f)
+	if !ok {
+		rejectClosed(&e.config, &e.errs, &e.stats, skipped)
+		return
+	}
+	submitted := e.stats.submitted()
+	fn := func() {
+		defer group.wg.Done()
+		if !group.start(id, e.errs.skip()) {
+			e.stats.skipped()
+			skipTask(skipped, ErrSkipped)
+			return
+		}
+		defer group.finish()
+		defer e.stats.started(submitted)()
+		e.errs.call(e.wrap(f))
+	}
+	e.lock.Lock()
+	e.queue.Enqueue(&queuedTask{fn: fn, group: group})
+	e.lock.Unlock()
+}
+
+func (e *stepExecutor) GoErr(f func() error) {
+	e.Go(func() {
+		e.errs.run(f)
+	})
+}
+
+func (e *stepExecutor) GoCtx(f func(context.Context)) {
+	ctx := e.tasks.context()
+	e.Go(func() {
+		e.tasks.run(ctx, f)
+	})
+}
+
+func (e *stepExecutor) Step() bool {
+	e.lock.Lock()
+	task, ok := e.queue.Dequeue()
+	e.lock.Unlock()
+	if ok {
+		task.fn()
+	}
+	return ok
+}
+
+func (e *stepExecutor) Pending() int {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return e.queue.Len()
+}
+
+func (e *stepExecutor) Wait(ctx context.Context) {
+	e.WaitResult(ctx)
+}
+
+// WaitResult executes queued units of work until none remain or the context is canceled, in which case the
+// remaining units of work are skipped
+func (e *stepExecutor) WaitResult(ctx context.Context) WaitResult {
+	e.run(ctx)
+	result, _ := e.life.wait(ctx, &e.tasks)
+	return result
+}
+
+func (e *stepExecutor) WaitErr(ctx context.Context) error {
+	e.Wait(ctx)
+	return e.errs.take()
+}
+
+// Shutdown rejects subsequent units of work and executes those queued, unless the context is canceled first
+func (e *stepExecutor) Shutdown(ctx context.Context) error {
+	e.life.setState(ExecutorDraining)
+	e.run(ctx)
+	err := errors.Join(e.life.shutdown(ctx, &e.tasks), e.errs.take())
+	return errors.Join(err, shutdownChild(ctx, e, &e.childLock, &e.childExecutor))
+}
+
+func (e *stepExecutor) Close() error {
+	ctx, cancel := e.life.closeContext()
+	defer cancel()
+	return e.Shutdown(ctx)
+}
+
+func (e *stepExecutor) State() ExecutorState {
+	return e.life.State()
+}
+
+func (e *stepExecutor) Stats() Stats {
+	return e.stats.snapshot(&e.errs)
+}
+
+// ChildExecutor returns a serial executor, since a unit of work waiting on this executor would otherwise wait for
+// itself
+func (e *stepExecutor) ChildExecutor() Executor {
+	e.childLock.RLock()
+	child := e.childExecutor
+	e.childLock.RUnlock()
+	if child != nil {
+		return child
+	}
+	e.childLock.Lock()
+	defer e.childLock.Unlock()
+	if e.childExecutor == nil {
+		e.childExecutor = newSerialExecutor(e.config)
+	}
+	return e.childExecutor
+}
+
+// run steps until no units of work are queued or the context is canceled
+func (e *stepExecutor) run(ctx context.Context) {
+	for ctx.Err() == nil {
+		if !e.Step() {
+			return
+		}
+	}
+}
+
+// drain removes the units of work belonging to canceled groups from the queue, skipping them
+func (e *stepExecutor) drain() {
+	e.lock.Lock()
+	var skipped, kept []*queuedTask
+	for task, ok := e.queue.Dequeue(); ok; task, ok = e.queue.Dequeue() {
+		if task.group.isCanceled() {
+			skipped = append(skipped, task)
+		} else {
+			kept = append(kept, task)
+		}
+	}
+	for _, task := range kept {
+		e.queue.Enqueue(task)
+	}
+	e.lock.Unlock()
+
+	for _, task := range skipped {
+		task.fn()
+	}
+}
@@ -0,0 +1,120 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StepExecutor(t *testing.T) {
+	e := NewStepExecutor()
+
+	executed := List[int]{}
+	for i := range 3 {
+		e.Go(func() {
+			executed.Append(i)
+		})
+	}
+	require.Equal(t, 3, e.Pending())
+	require.Empty(t, executed.Values())
+
+	require.True(t, e.Step())
+	require.Equal(t, []int{0}, executed.Values())
+	require.Equal(t, 2, e.Pending())
+
+	// units of work queued while executing are executed by Wait
+	e.Go(func() {
+		e.Go(func() {
+			executed.Append(4)
+		})
+		executed.Append(3)
+	})
+	result := e.(ResultExecutor).WaitResult(context.Background())
+	require.Equal(t, []int{0, 1, 2, 3, 4}, executed.Values())
+	require.Equal(t, 5, result.Completed)
+	require.Zero(t, e.Pending())
+	require.False(t, e.Step())
+}
+
+func Test_StepExecutorCancel(t *testing.T) {
+	e := NewStepExecutor(WithRetainSkipped())
+
+	executed := 0
+	e.Go(func() {
+		executed++
+	})
+	e.Go(func() {
+		executed++
+	})
+	require.True(t, e.Step())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result := e.(ResultExecutor).WaitResult(ctx)
+	require.Equal(t, 1, executed)
+	require.Equal(t, 1, result.Completed)
+	require.Equal(t, 1, result.Skipped)
+	require.Len(t, result.SkippedTasks, 1)
+	require.Zero(t, e.Pending())
+
+	// subsequent units of work are executed
+	e.Go(func() {
+		executed++
+	})
+	e.Wait(context.Background())
+	require.Equal(t, 2, executed)
+}
+
+func Test_StepExecutorErrors(t *testing.T) {
+	e := NewStepExecutor(WithPanicPolicy(PanicCapture)).(ErrorExecutor)
+
+	e.GoErr(func() error {
+		return fmt.Errorf("failed")
+	})
+	e.Go(func() {
+		panic("panicked")
+	})
+	err := e.WaitErr(context.Background())
+	require.ErrorContains(t, err, "failed")
+	var p PanicError
+	require.ErrorAs(t, err, &p)
+}
+
+func Test_StepExecutorChild(t *testing.T) {
+	e := NewStepExecutor()
+	ctx := SetContextExecutor(context.Background(), "", e)
+
+	executed := false
+	e.Go(func() {
+		ctx := ctx
+		require.Equal(t, e, ContextExecutor(&ctx, ""))
+		// the child executes serially, so waiting does not wait on the unit of work being executed
+		nested := ContextExecutor(&ctx, "")
+		nested.Go(func() {
+			executed = true
+		})
+		nested.Wait(ctx)
+	})
+	e.Wait(ctx)
+	require.True(t, executed)
+}
+
+func Test_StepExecutorShutdown(t *testing.T) {
+	e := NewStepExecutor().(ShutdownExecutor)
+
+	executed := 0
+	e.Go(func() {
+		executed++
+	})
+	require.NoError(t, e.Shutdown(context.Background()))
+	require.Equal(t, 1, executed)
+	require.Equal(t, ExecutorClosed, e.State())
+
+	e.Go(func() {
+		executed++
+	})
+	require.ErrorIs(t, e.(ErrorExecutor).WaitErr(context.Background()), ErrExecutorClosed)
+	require.Equal(t, 1, executed)
+}
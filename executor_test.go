@@ -2,6 +2,7 @@ package sync
 
 import (
 	"context"
+	"fmt"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -47,10 +48,10 @@ func Test_Executor(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		for _, errGroup := range []bool{false, true} {
+		for _, bounded := range []bool{false, true} {
 			t.Run(test.name, func(t *testing.T) {
 				e := NewExecutor(test.maxConcurrency)
-				if !errGroup && test.maxConcurrency > 1 {
+				if !bounded && test.maxConcurrency > 1 {
 					e = &queuedExecutor{
 						maxConcurrency: test.maxConcurrency,
 					}
@@ -79,3 +80,394 @@ func Test_Executor(t *testing.T) {
 		}
 	}
 }
+
+func Test_ErrorExecutor(t *testing.T) {
+	for _, test := range allOptionExecutors() {
+		t.Run(test.name, func(t *testing.T) {
+			t.Run("joins errors", func(t *testing.T) {
+				e := test.executor().(ErrorExecutor)
+
+				e.GoErr(func() error {
+					return fmt.Errorf("error 1")
+				})
+				e.GoErr(func() error {
+					return nil
+				})
+				e.GoErr(func() error {
+					panic("error 2")
+				})
+
+				err := e.WaitErr(context.Background())
+				require.ErrorContains(t, err, "error 1")
+				require.ErrorContains(t, err, "error 2")
+				var p PanicError
+				require.ErrorAs(t, err, &p)
+				require.Equal(t, "error 2", p.Value)
+				require.Contains(t, p.Stack, "github.com/anchore/go-sync")
+
+				// errors are only reported once
+				require.NoError(t, e.WaitErr(context.Background()))
+			})
+
+			t.Run("fail fast", func(t *testing.T) {
+				if test.name == "unbounded" {
+					// all functions start immediately, so ordering is not deterministic
+					t.Skip()
+				}
+				e := test.executor(WithFailFast()).(ErrorExecutor)
+
+				executed := atomic.Int32{}
+				e.GoErr(func() error {
+					executed.Add(1)
+					return fmt.Errorf("failed")
+				})
+				e.GoErr(func() error {
+					executed.Add(1)
+					return nil
+				})
+				e.Go(func() {
+					executed.Add(1)
+				})
+
+				require.ErrorContains(t, e.WaitErr(context.Background()), "failed")
+				require.Equal(t, int32(1), executed.Load())
+
+				// once errors are reported, the executor continues executing
+				e.Go(func() {
+					executed.Add(1)
+				})
+				require.NoError(t, e.WaitErr(context.Background()))
+				require.Equal(t, int32(2), executed.Load())
+			})
+		})
+	}
+}
+
+type optionExecutor struct {
+	name     string
+	executor func(opts ...Option) Executor
+}
+
+// optionExecutors returns constructors for each built-in executor accepting options, bounded executors
+// have a concurrency of 1 so tests may rely on ordering
+func optionExecutors() []optionExecutor {
+	return []optionExecutor{
+		{
+			name: "serial",
+			executor: func(opts ...Option) Executor {
+				return NewExecutor(0, opts...)
+			},
+		},
+		{
+			name: "unbounded",
+			executor: func(opts ...Option) Executor {
+				return NewExecutor(-1, opts...)
+			},
+		},
+		{
+			name: "bounded",
+			executor: func(opts ...Option) Executor {
+				return NewExecutor(1, opts...)
+			},
+		},
+		{
+			name: "queued",
+			executor: func(opts ...Option) Executor {
+				return NewQueuedExecutor(1, opts...)
+			},
+		},
+	}
+}
+
+// allOptionExecutors returns the constructors from optionExecutors along with the other built-in executors
+// accepting options, which implement ExecutorStats, ShutdownExecutor and ResultExecutor
+func allOptionExecutors() []optionExecutor {
+	return append(optionExecutors(),
+		optionExecutor{
+			name: "weighted",
+			executor: func(opts ...Option) Executor {
+				return NewWeightedExecutor(1, opts...)
+			},
+		},
+		optionExecutor{
+			name: "priority",
+			executor: func(opts ...Option) Executor {
+				return NewPriorityExecutor(1, opts...)
+			},
+		},
+		optionExecutor{
+			name: "adaptive",
+			executor: func(opts ...Option) Executor {
+				return NewAdaptiveExecutor(1, 1, opts...)
+			},
+		},
+		optionExecutor{
+			name: "rate limited",
+			executor: func(opts ...Option) Executor {
+				return NewRateLimitedExecutor(1, 1000, 10, opts...)
+			},
+		},
+	)
+}
+
+func Test_ExecutorPanicPolicy(t *testing.T) {
+	for _, test := range optionExecutors() {
+		t.Run(test.name, func(t *testing.T) {
+			t.Run("capture", func(t *testing.T) {
+				e := test.executor(WithPanicPolicy(PanicCapture)).(ErrorExecutor)
+
+				executed := atomic.Int32{}
+				e.Go(func() {
+					panic("captured")
+				})
+				// subsequent functions continue to execute
+				e.Go(func() {
+					executed.Add(1)
+				})
+
+				err := e.WaitErr(context.Background())
+				var p PanicError
+				require.ErrorAs(t, err, &p)
+				require.Equal(t, "captured", p.Value)
+				require.Contains(t, p.Stack, "github.com/anchore/go-sync")
+				require.Equal(t, int32(1), executed.Load())
+			})
+
+			t.Run("handler", func(t *testing.T) {
+				handled := List[string]{}
+				e := test.executor(WithPanicHandler(func(p PanicError) {
+					handled.Append(fmt.Sprint(p.Value))
+				})).(ErrorExecutor)
+
+				e.Go(func() {
+					panic("handled")
+				})
+
+				require.NoError(t, e.WaitErr(context.Background()))
+				require.Equal(t, []string{"handled"}, handled.Values())
+			})
+		})
+	}
+
+	t.Run("repanic", func(t *testing.T) {
+		var recovered any
+		func() {
+			defer func() {
+				recovered = recover()
+			}()
+			NewExecutor(0).Go(func() {
+				panic("repanic")
+			})
+		}()
+		p, ok := recovered.(PanicError)
+		require.True(t, ok)
+		require.Equal(t, "repanic", p.Value)
+		require.Contains(t, p.Stack, "github.com/anchore/go-sync")
+	})
+}
+
+func Test_ContextualExecutor(t *testing.T) {
+	for _, test := range allOptionExecutors() {
+		t.Run(test.name, func(t *testing.T) {
+			t.Run("canceled by wait", func(t *testing.T) {
+				if test.name == "serial" {
+					// functions execute during GoCtx, before Wait could be called
+					t.Skip()
+				}
+				e := test.executor().(ContextualExecutor)
+
+				errStop := fmt.Errorf("stop")
+				ctx, cancel := context.WithCancelCause(context.Background())
+
+				started := make(chan struct{})
+				done := make(chan error, 1)
+				e.GoCtx(func(ctx context.Context) {
+					close(started)
+					<-ctx.Done()
+					done <- context.Cause(ctx)
+				})
+
+				<-started
+				cancel(errStop)
+				e.Wait(ctx)
+				require.ErrorIs(t, <-done, errStop)
+			})
+
+			t.Run("task timeout", func(t *testing.T) {
+				e := test.executor(WithTaskTimeout(time.Millisecond)).(ContextualExecutor)
+
+				var err error
+				e.GoCtx(func(ctx context.Context) {
+					<-ctx.Done()
+					err = ctx.Err()
+				})
+				e.Wait(context.Background())
+				require.ErrorIs(t, err, context.DeadlineExceeded)
+			})
+
+			t.Run("task timeout clock", func(t *testing.T) {
+				if test.name == "serial" {
+					// functions execute during GoCtx, so the clock could not be advanced
+					t.Skip()
+				}
+				clock := NewFakeClock(time.Now())
+				e := test.executor(WithTaskTimeout(time.Hour), WithClock(clock)).(ContextualExecutor)
+
+				var err error
+				e.GoCtx(func(ctx context.Context) {
+					<-ctx.Done()
+					err = ctx.Err()
+				})
+				require.NoError(t, clock.BlockUntil(context.Background(), 1))
+				clock.Advance(2 * time.Hour)
+				e.Wait(context.Background())
+				require.ErrorIs(t, err, context.DeadlineExceeded)
+			})
+		})
+	}
+}
+
+func Test_Resizable(t *testing.T) {
+	for _, test := range allOptionExecutors() {
+		e := test.executor()
+		r, ok := e.(Resizable)
+		if !ok || test.name == "adaptive" {
+			// the adaptive executor resizes itself
+			continue
+		}
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, 1, r.MaxConcurrency())
+
+			// each unit of work reports how many were running when it started
+			running := atomic.Int32{}
+			started := make(chan int32, 6)
+			release := make(chan struct{})
+			submitted := make(chan struct{})
+			go func() {
+				defer close(submitted)
+				// bounded executors block when Go is called at the maximum concurrency
+				for range 6 {
+					e.Go(func() {
+						started <- running.Add(1)
+						<-release
+						running.Add(-1)
+					})
+				}
+			}()
+			require.Equal(t, int32(1), <-started)
+
+			// grow, the units of work block until released, so 3 are running
+			r.SetMaxConcurrency(3)
+			require.Equal(t, 3, r.MaxConcurrency())
+			<-started
+			<-started
+			require.Equal(t, int32(3), running.Load())
+
+			// shrink, the remaining units of work only start once fewer than 1 are running, so execute alone
+			r.SetMaxConcurrency(1)
+			close(release)
+			for range 3 {
+				require.Equal(t, int32(1), <-started)
+			}
+
+			<-submitted
+			e.Wait(context.Background())
+			require.Equal(t, int32(0), running.Load())
+		})
+	}
+}
+
+func Test_ExecutorStats(t *testing.T) {
+	for _, test := range allOptionExecutors() {
+		t.Run(test.name, func(t *testing.T) {
+			e := test.executor(WithPanicPolicy(PanicCapture), WithFailFast()).(ErrorExecutor)
+
+			for range 3 {
+				e.Go(func() {
+					time.Sleep(time.Millisecond)
+				})
+			}
+			e.Wait(context.Background())
+			e.Go(func() {
+				panic("stats")
+			})
+			e.Wait(context.Background())
+
+			// failing fast skips subsequent units of work until errors are reported
+			e.Go(func() {})
+			require.Error(t, e.WaitErr(context.Background()))
+
+			s := e.(ExecutorStats).Stats()
+			require.Equal(t, int64(0), s.Queued)
+			require.Equal(t, int64(0), s.Active)
+			require.GreaterOrEqual(t, s.PeakActive, int64(1))
+			require.Equal(t, int64(4), s.Completed)
+			require.Equal(t, int64(1), s.Panicked)
+			require.Equal(t, int64(4), s.RunTime.Count)
+			require.Equal(t, int64(4), s.WaitTime.Count)
+			require.GreaterOrEqual(t, s.RunTime.Sum, 3*time.Millisecond)
+			require.GreaterOrEqual(t, s.RunTime.Quantile(0.5), time.Millisecond)
+			require.Equal(t, int64(1), s.Canceled)
+		})
+	}
+}
+
+func Test_ExecutorStatsClock(t *testing.T) {
+	for _, test := range allOptionExecutors() {
+		t.Run(test.name, func(t *testing.T) {
+			clock := NewFakeClock(time.Now())
+			e := test.executor(WithClock(clock))
+
+			e.Go(func() {
+				clock.Advance(time.Hour)
+			})
+			e.Wait(context.Background())
+
+			// wait and run times are measured by the configured clock
+			s := e.(ExecutorStats).Stats()
+			require.Equal(t, time.Hour, s.RunTime.Sum)
+			require.Equal(t, time.Duration(0), s.WaitTime.Sum)
+		})
+	}
+}
+
+func Test_ExecutorStatsRejected(t *testing.T) {
+	e := NewQueuedExecutor(1, WithQueueCapacity(1), WithRejectionPolicy(RejectDrop))
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	e.Go(func() {
+		close(started)
+		<-release
+	})
+	<-started
+	e.Go(func() {})
+	e.Go(func() {})
+
+	s := e.(ExecutorStats).Stats()
+	require.Equal(t, int64(1), s.Active)
+	require.Equal(t, int64(1), s.Queued)
+	require.Equal(t, int64(1), s.Rejected)
+
+	close(release)
+	e.Wait(context.Background())
+	require.Equal(t, int64(2), e.(ExecutorStats).Stats().Completed)
+}
+
+func Test_Histogram(t *testing.T) {
+	h := Histogram{
+		Count: 4,
+		Sum:   10 * time.Millisecond,
+		Max:   7 * time.Millisecond,
+		Buckets: []HistogramBucket{
+			{UpperBound: time.Millisecond, Count: 3},
+			{UpperBound: 8 * time.Millisecond, Count: 1},
+		},
+	}
+	require.Equal(t, 2500*time.Microsecond, h.Mean())
+	require.Equal(t, time.Millisecond, h.Quantile(0.5))
+	require.Equal(t, 7*time.Millisecond, h.Quantile(0.9))
+	require.Equal(t, 7*time.Millisecond, h.Quantile(1))
+	require.Equal(t, time.Duration(0), Histogram{}.Mean())
+	require.Equal(t, time.Duration(0), Histogram{}.Quantile(0.5))
+}
@@ -2,39 +2,105 @@ package sync
 
 import (
 	"context"
-	"sync"
-	"sync/atomic"
+	"errors"
 )
 
 // unboundedExecutor executes all Go calls without any specific bound
 type unboundedExecutor struct {
-	canceled atomic.Bool
-	wg       sync.WaitGroup
+	config executorConfig
+	life   lifecycle
+	errs   taskErrors
+	tasks  taskContexts
+	stats  taskStats
+	taskLabels
+}
+
+var _ interface {
+	ErrorExecutor
+	ContextualExecutor
+	SkipExecutor
+	ExecutorStats
+	ShutdownExecutor
+	ResultExecutor
+	namedExecutor
+} = (*unboundedExecutor)(nil)
+
+func newUnboundedExecutor(config executorConfig) *unboundedExecutor {
+	return &unboundedExecutor{
+		config: config,
+		life:   newLifecycle(config),
+		errs:   newTaskErrors(config),
+		tasks:  newTaskContexts(config),
+		stats:  newTaskStats(config),
+	}
 }
 
 func (e *unboundedExecutor) Go(f func()) {
-	e.wg.Add(1)
+	e.GoSkip(f, nil)
+}
+
+func (e *unboundedExecutor) GoSkip(f func(), skipped func(error)) {
+	group, id, ok := e.life.add(f)
+	if !ok {
+		rejectClosed(&e.config, &e.errs, &e.stats, skipped)
+		return
+	}
+	submitted := e.stats.submitted()
 	go func() {
-		defer e.wg.Done()
-		if e.canceled.Load() {
+		defer group.wg.Done()
+		if !group.start(id, e.errs.skip()) {
+			e.stats.skipped()
+			skipTask(skipped, ErrSkipped)
 			return
 		}
-		f()
+		defer group.finish()
+		defer e.stats.started(submitted)()
+		e.errs.call(e.wrap(f))
 	}()
 }
 
+func (e *unboundedExecutor) GoErr(f func() error) {
+	e.Go(func() {
+		e.errs.run(f)
+	})
+}
+
+func (e *unboundedExecutor) GoCtx(f func(context.Context)) {
+	ctx := e.tasks.context()
+	e.Go(func() {
+		e.tasks.run(ctx, f)
+	})
+}
+
 func (e *unboundedExecutor) Wait(ctx context.Context) {
-	e.canceled.Store(ctx.Err() != nil)
+	e.WaitResult(ctx)
+}
 
-	done := make(chan struct{})
-	go func() {
-		e.wg.Wait()
-		close(done)
-	}()
+func (e *unboundedExecutor) WaitResult(ctx context.Context) WaitResult {
+	result, _ := e.life.wait(ctx, &e.tasks)
+	return result
+}
 
-	select {
-	case <-ctx.Done():
-		e.canceled.Store(true)
-	case <-done:
-	}
+func (e *unboundedExecutor) WaitErr(ctx context.Context) error {
+	e.Wait(ctx)
+	return e.errs.take()
+}
+
+func (e *unboundedExecutor) Shutdown(ctx context.Context) error {
+	err := e.life.shutdown(ctx, &e.tasks)
+	return errors.Join(err, e.errs.take())
+}
+
+func (e *unboundedExecutor) Close() error {
+	ctx, cancel := e.life.closeContext()
+	defer cancel()
+	return e.Shutdown(ctx)
+}
+
+func (e *unboundedExecutor) State() ExecutorState {
+	return e.life.State()
+}
+
+func (e *unboundedExecutor) Stats() Stats {
+	return e.stats.snapshot(&e.errs)
 }
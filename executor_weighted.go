@@ -0,0 +1,195 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/anchore/go-sync/internal/semaphore"
+)
+
+// WeightedExecutor is an Executor where each unit of work uses a portion of the total capacity
+type WeightedExecutor interface {
+	Executor
+
+	// GoWeighted adds a unit of work using the given weight of the executor capacity, blocking until enough
+	// capacity is available. Weights are limited to the range 1 to the executor capacity when submitted; if the
+	// capacity is reduced while waiting, a weight larger than the new capacity executes when nothing else is
+	// executing. Go uses a weight of 1
+	GoWeighted(weight int64, fn func())
+}
+
+// weightedExecutor is an Executor that executes units of work, blocking when Go is called until enough of the
+// capacity is available for the weight of the unit of work
+type weightedExecutor struct {
+	life          lifecycle
+	sem           *semaphore.Weighted
+	childLock     sync.RWMutex
+	childExecutor Executor
+	config        executorConfig
+	errs          taskErrors
+	tasks         taskContexts
+	stats         taskStats
+	taskLabels
+}
+
+var _ interface {
+	WeightedExecutor
+	ErrorExecutor
+	ContextualExecutor
+	SkipExecutor
+	ExecutorStats
+	ShutdownExecutor
+	ResultExecutor
+	ChildExecutor
+	Resizable
+	namedExecutor
+} = (*weightedExecutor)(nil)
+
+// NewWeightedExecutor returns an executor with the given total capacity, where each unit of work uses a weight of
+// the capacity. Go blocks until enough capacity is available, waiting units of work are started in the order they
+// were submitted. A capacity < 1 is treated as 1
+func NewWeightedExecutor(capacity int64, opts ...Option) WeightedExecutor {
+	return newWeightedExecutor(capacity, newExecutorConfig(opts...))
+}
+
+func newWeightedExecutor(capacity int64, config executorConfig) *weightedExecutor {
+	return &weightedExecutor{
+		sem:    semaphore.NewWeighted(max(capacity, 1)),
+		config: config,
+		life:   newLifecycle(config),
+		errs:   newTaskErrors(config),
+		tasks:  newTaskContexts(config),
+		stats:  newTaskStats(config),
+	}
+}
+
+func (e *weightedExecutor) Go(f func()) {
+	e.goWeighted(1, f, nil)
+}
+
+func (e *weightedExecutor) GoSkip(f func(), skipped func(error)) {
+	e.goWeighted(1, f, skipped)
+}
+
+func (e *weightedExecutor) GoWeighted(weight int64, f func()) {
+	e.goWeighted(weight, f, nil)
+}
+
+// goWeighted adds the unit of work using the weight, calling skipped if it will not be executed
+func (e *weightedExecutor) goWeighted(weight int64, f func(), skipped func(error)) {
+	weight = min(max(weight, 1), e.sem.Size())
+	group, id, ok := e.life.add(f)
+	if !ok {
+		rejectClosed(&e.config, &e.errs, &e.stats, skipped)
+		return
+	}
+	submitted := e.stats.submitted()
+	// acquiring is abandoned when a call to Wait observes a canceled context
+	if err := e.sem.Acquire(e.tasks.context(), weight); err != nil {
+		e.stats.skipped()
+		group.skip(id)
+		group.wg.Done()
+		skipTask(skipped, ErrSkipped)
+		return
+	}
+	go func() {
+		defer group.wg.Done()
+		defer e.sem.Release(weight)
+		if !group.start(id, e.errs.skip()) {
+			e.stats.skipped()
+			skipTask(skipped, ErrSkipped)
+			return
+		}
+		defer group.finish()
+		defer e.stats.started(submitted)()
+		e.errs.call(e.wrap(f))
+	}()
+}
+
+func (e *weightedExecutor) GoErr(f func() error) {
+	e.Go(func() {
+		e.errs.run(f)
+	})
+}
+
+func (e *weightedExecutor) GoCtx(f func(context.Context)) {
+	ctx := e.tasks.context()
+	e.Go(func() {
+		e.tasks.run(ctx, f)
+	})
+}
+
+func (e *weightedExecutor) Wait(ctx context.Context) {
+	e.WaitResult(ctx)
+}
+
+func (e *weightedExecutor) WaitResult(ctx context.Context) WaitResult {
+	result, _ := e.life.wait(ctx, &e.tasks)
+	return result
+}
+
+func (e *weightedExecutor) WaitErr(ctx context.Context) error {
+	e.Wait(ctx)
+	return e.errs.take()
+}
+
+// Shutdown shuts down the executor and then the child executor, if one was created
+func (e *weightedExecutor) Shutdown(ctx context.Context) error {
+	err := errors.Join(e.life.shutdown(ctx, &e.tasks), e.errs.take())
+	return errors.Join(err, shutdownChild(ctx, e, &e.childLock, &e.childExecutor))
+}
+
+func (e *weightedExecutor) Close() error {
+	ctx, cancel := e.life.closeContext()
+	defer cancel()
+	return e.Shutdown(ctx)
+}
+
+func (e *weightedExecutor) State() ExecutorState {
+	return e.life.State()
+}
+
+func (e *weightedExecutor) Stats() Stats {
+	return e.stats.snapshot(&e.errs)
+}
+
+func (e *weightedExecutor) ChildExecutor() Executor {
+	e.childLock.RLock()
+	child := e.childExecutor
+	e.childLock.RUnlock()
+	if child != nil {
+		return child
+	}
+	e.childLock.Lock()
+	defer e.childLock.Unlock()
+	if e.childExecutor == nil {
+		switch e.config.childStrategy {
+		case ChildBounded:
+			// create child executor with the same capacity, so nested units of work do not wait on capacity
+			// held by their parents
+			e.childExecutor = newWeightedExecutor(e.sem.Size(), e.config)
+		default:
+			e.childExecutor = e.config.newChild(e.MaxConcurrency())
+			if e.childExecutor == nil {
+				e.childExecutor = e
+			}
+		}
+	}
+	return e.childExecutor
+}
+
+// MaxConcurrency returns the capacity of the executor
+func (e *weightedExecutor) MaxConcurrency() int {
+	return int(e.sem.Size())
+}
+
+// SetMaxConcurrency changes the capacity of the executor
+func (e *weightedExecutor) SetMaxConcurrency(capacity int) {
+	e.sem.Resize(int64(max(capacity, 1)))
+	e.childLock.RLock()
+	defer e.childLock.RUnlock()
+	if child, ok := e.childExecutor.(Resizable); ok && e.childExecutor != e {
+		child.SetMaxConcurrency(capacity)
+	}
+}
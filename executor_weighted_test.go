@@ -0,0 +1,123 @@
+package sync
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/go-sync/internal/stats"
+)
+
+func Test_WeightedExecutor(t *testing.T) {
+	const capacity = 10
+	e := NewWeightedExecutor(capacity)
+
+	used := stats.Tracked[int64]{}
+	for i := range 100 {
+		weight := int64(i%capacity + 1)
+		e.GoWeighted(weight, func() {
+			used.Add(weight)
+			defer used.Add(-weight)
+			time.Sleep(10 * time.Microsecond)
+		})
+	}
+	e.Wait(context.Background())
+
+	require.LessOrEqual(t, used.Max(), int64(capacity))
+	require.Equal(t, int64(0), used.Val())
+}
+
+func Test_WeightedExecutorBlocks(t *testing.T) {
+	e := NewWeightedExecutor(3)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	e.GoWeighted(2, func() {
+		close(started)
+		<-release
+	})
+	<-started
+
+	// fits in the remaining capacity
+	e.Go(func() {})
+
+	submitted := make(chan struct{})
+	go func() {
+		defer close(submitted)
+		e.GoWeighted(2, func() {})
+	}()
+
+	select {
+	case <-submitted:
+		require.Fail(t, "GoWeighted should block until capacity is available")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(release)
+	<-submitted
+	e.Wait(context.Background())
+}
+
+func Test_WeightedExecutorLimitsWeight(t *testing.T) {
+	e := NewWeightedExecutor(2)
+
+	executed := false
+	// a weight larger than the capacity would never execute
+	e.GoWeighted(100, func() {
+		executed = true
+	})
+	e.Wait(context.Background())
+	require.True(t, executed)
+}
+
+func Test_WeightedExecutorCancel(t *testing.T) {
+	// Wait returns without waiting for the executing unit of work, which is released afterward
+	e := NewWeightedExecutor(1, WithDrainTimeout(-1))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	e.Go(func() {
+		close(started)
+		<-release
+	})
+	<-started
+
+	executed := false
+	submitted := make(chan struct{})
+	go func() {
+		defer close(submitted)
+		// blocked waiting for capacity until Wait is canceled
+		e.Go(func() {
+			executed = true
+		})
+	}()
+
+	// units of work submitted after Wait returns are not discarded, so allow time to begin waiting for capacity
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	e.Wait(ctx)
+	<-submitted
+	close(release)
+
+	require.False(t, executed)
+}
+
+func Test_WeightedExecutorSubcontext(t *testing.T) {
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	ctx := SetContextExecutor(context.TODO(), "", NewWeightedExecutor(1))
+	ContextExecutor(&ctx, "").Go(func() {
+		// context should be replaced with a secondary executor
+		ContextExecutor(&ctx, "").Go(func() {
+			// context should be replaced again with a tertiary executor
+			ContextExecutor(&ctx, "").Go(func() {
+				wg.Done()
+			})
+		})
+	})
+	wg.Wait() // only done by sub-executor
+}
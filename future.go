@@ -0,0 +1,195 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+)
+
+// Future provides the result of a function executed asynchronously
+type Future[T any] interface {
+	// Get waits for and returns the result, or the context error if the context is canceled first
+	Get(ctx context.Context) (T, error)
+
+	// Done returns a channel which is closed once the result is available
+	Done() <-chan struct{}
+
+	// Provider returns a Provider which waits for the result, errors result in the zero value
+	Provider() Provider[T]
+}
+
+// Submit executes the function using the executor, returning a Future with the result. Panics are returned as
+// a PanicError. If the executor does not execute the function, the Future is completed with the reason, such as
+// ErrExecutorClosed, ErrQueueFull or ErrSkipped, when the executor is a SkipExecutor, as all executors in this
+// package are; otherwise the Future is never completed
+func Submit[T any](executor Executor, fn func() (T, error)) Future[T] {
+	f := newFuture[T]()
+	goSkip(executor, func() {
+		f.complete(call(fn))
+	}, func(err error) {
+		var zero T
+		f.complete(zero, err)
+	})
+	return f
+}
+
+// Then returns a Future with the result of calling fn with the successful result of the provided Future.
+// If the provided Future fails, the error is returned without calling fn
+func Then[T, U any](future Future[T], fn func(T) (U, error)) Future[U] {
+	f := newFuture[U]()
+	go func() {
+		value, err := future.Get(context.Background())
+		if err != nil {
+			var zero U
+			f.complete(zero, err)
+			return
+		}
+		f.complete(call(func() (U, error) {
+			return fn(value)
+		}))
+	}()
+	return f
+}
+
+// All returns a Future with the results of all provided futures in the same order, or the first error to occur,
+// without waiting for the remaining futures
+func All[T any](futures ...Future[T]) Future[[]T] {
+	f := newFuture[[]T]()
+	type result struct {
+		index int
+		err   error
+	}
+	results := make(chan result, len(futures))
+	for i, future := range futures {
+		go func() {
+			// stop waiting once the result is known
+			select {
+			case <-future.Done():
+			case <-f.done:
+				return
+			}
+			_, err := future.Get(context.Background())
+			results <- result{index: i, err: err}
+		}()
+	}
+	go func() {
+		values := make([]T, len(futures))
+		for range futures {
+			r := <-results
+			if r.err != nil {
+				f.complete(nil, r.err)
+				return
+			}
+			values[r.index], _ = futures[r.index].Get(context.Background())
+		}
+		f.complete(values, nil)
+	}()
+	return f
+}
+
+// Any returns a Future with the first successful result of the provided futures, or all errors joined with
+// errors.Join if none succeed
+func Any[T any](futures ...Future[T]) Future[T] {
+	f := newFuture[T]()
+	if len(futures) == 0 {
+		var zero T
+		f.complete(zero, ErrNoFutures)
+		return f
+	}
+	type result struct {
+		value T
+		err   error
+	}
+	results := make(chan result, len(futures))
+	for _, future := range futures {
+		go func() {
+			// stop waiting once the result is known
+			select {
+			case <-future.Done():
+			case <-f.done:
+				return
+			}
+			value, err := future.Get(context.Background())
+			results <- result{value: value, err: err}
+		}()
+	}
+	go func() {
+		var errs []error
+		for range futures {
+			r := <-results
+			if r.err == nil {
+				f.complete(r.value, nil)
+				return
+			}
+			errs = append(errs, r.err)
+		}
+		var zero T
+		f.complete(zero, errors.Join(errs...))
+	}()
+	return f
+}
+
+// future is the Future implementation, completed exactly once
+type future[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+var _ Future[int] = (*future[int])(nil)
+
+func newFuture[T any]() *future[T] {
+	return &future[T]{
+		done: make(chan struct{}),
+	}
+}
+
+func (f *future[T]) complete(value T, err error) {
+	f.value = value
+	f.err = err
+	close(f.done)
+}
+
+func (f *future[T]) Get(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+func (f *future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+func (f *future[T]) Provider() Provider[T] {
+	return futureProvider[T]{future: f}
+}
+
+// futureProvider adapts a Future to the Provider interface
+type futureProvider[T any] struct {
+	future Future[T]
+}
+
+var _ Provider[int] = (*futureProvider[int])(nil)
+
+func (p futureProvider[T]) Get() T {
+	value, err := p.future.Get(context.Background())
+	if err != nil {
+		var zero T
+		return zero
+	}
+	return value
+}
+
+// call executes the function, returning any panic as a PanicError
+func call[T any](fn func() (T, error)) (value T, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = PanicError{Value: v, Stack: string(debug.Stack())}
+		}
+	}()
+	return fn()
+}
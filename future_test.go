@@ -0,0 +1,186 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Submit(t *testing.T) {
+	e := NewExecutor(2)
+
+	f := Submit(e, func() (int, error) {
+		return 42, nil
+	})
+	value, err := f.Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 42, value)
+
+	select {
+	case <-f.Done():
+	default:
+		require.Fail(t, "future should be done")
+	}
+
+	f = Submit(e, func() (int, error) {
+		return 0, fmt.Errorf("failed")
+	})
+	_, err = f.Get(context.Background())
+	require.ErrorContains(t, err, "failed")
+
+	f = Submit(e, func() (int, error) {
+		panic("oh no")
+	})
+	_, err = f.Get(context.Background())
+	var p PanicError
+	require.ErrorAs(t, err, &p)
+	require.Equal(t, "oh no", p.Value)
+}
+
+func Test_SubmitSkipped(t *testing.T) {
+	// functions which are not executed complete the future with the reason
+	e := NewExecutor(2, WithFailFast())
+	e.(ErrorExecutor).GoErr(func() error {
+		return fmt.Errorf("failed")
+	})
+	e.Wait(context.Background())
+	_, err := Submit(e, func() (int, error) {
+		return 1, nil
+	}).Get(context.Background())
+	require.ErrorIs(t, err, ErrSkipped)
+	require.Error(t, e.(ErrorExecutor).WaitErr(context.Background()))
+
+	require.NoError(t, e.(ShutdownExecutor).Close())
+	f := Submit(e, func() (int, error) {
+		return 1, nil
+	})
+	_, err = f.Get(context.Background())
+	require.ErrorIs(t, err, ErrExecutorClosed)
+
+	// combinators complete rather than waiting indefinitely
+	_, err = Then(f, func(i int) (int, error) {
+		return i, nil
+	}).Get(context.Background())
+	require.ErrorIs(t, err, ErrExecutorClosed)
+	_, err = All(f, newFuture[int]()).Get(context.Background())
+	require.ErrorIs(t, err, ErrExecutorClosed)
+}
+
+func Test_FutureGetCanceled(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	f := Submit(NewExecutor(-1), func() (int, error) {
+		<-release
+		return 1, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_, err := f.Get(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func Test_FutureProvider(t *testing.T) {
+	f := Submit(NewExecutor(-1), func() (string, error) {
+		return "value", nil
+	})
+
+	var p Provider[string] = f.Provider()
+	require.Equal(t, "value", p.Get())
+
+	f = Submit(NewExecutor(-1), func() (string, error) {
+		return "ignored", fmt.Errorf("failed")
+	})
+	require.Equal(t, "", f.Provider().Get())
+}
+
+func Test_Then(t *testing.T) {
+	e := NewExecutor(-1)
+
+	f := Then(Submit(e, func() (int, error) {
+		return 21, nil
+	}), func(v int) (string, error) {
+		return strconv.Itoa(v * 2), nil
+	})
+	value, err := f.Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "42", value)
+
+	called := false
+	f = Then(Submit(e, func() (int, error) {
+		return 0, fmt.Errorf("first failed")
+	}), func(v int) (string, error) {
+		called = true
+		return "", nil
+	})
+	_, err = f.Get(context.Background())
+	require.ErrorContains(t, err, "first failed")
+	require.False(t, called)
+}
+
+func Test_All(t *testing.T) {
+	e := NewExecutor(-1)
+
+	var futures []Future[int]
+	for i := range 10 {
+		futures = append(futures, Submit(e, func() (int, error) {
+			// complete in reverse order
+			time.Sleep(time.Duration(10-i) * time.Millisecond)
+			return i, nil
+		}))
+	}
+	values, err := All(futures...).Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, values)
+
+	futures = append(futures, Submit(e, func() (int, error) {
+		return 0, fmt.Errorf("failed")
+	}))
+	_, err = All(futures...).Get(context.Background())
+	require.ErrorContains(t, err, "failed")
+
+	values, err = All[int]().Get(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, values)
+}
+
+func Test_Any(t *testing.T) {
+	e := NewExecutor(-1)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	value, err := Any(
+		Submit(e, func() (int, error) {
+			<-release
+			return 1, nil
+		}),
+		Submit(e, func() (int, error) {
+			return 0, fmt.Errorf("failed")
+		}),
+		Submit(e, func() (int, error) {
+			return 3, nil
+		}),
+	).Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, value)
+
+	_, err = Any(
+		Submit(e, func() (int, error) {
+			return 0, fmt.Errorf("failed 1")
+		}),
+		Submit(e, func() (int, error) {
+			return 0, fmt.Errorf("failed 2")
+		}),
+	).Get(context.Background())
+	require.ErrorContains(t, err, "failed 1")
+	require.ErrorContains(t, err, "failed 2")
+
+	_, err = Any[int]().Get(context.Background())
+	require.ErrorIs(t, err, ErrNoFutures)
+}
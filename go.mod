@@ -2,10 +2,7 @@ module github.com/anchore/go-sync
 
 go 1.25.0
 
-require (
-	github.com/stretchr/testify v1.11.1
-	golang.org/x/sync v0.22.0
-)
+require github.com/stretchr/testify v1.11.1
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
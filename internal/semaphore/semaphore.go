@@ -0,0 +1,121 @@
+package semaphore
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// Weighted is a weighted semaphore which may be resized while in use. Waiters are admitted in the order they
+// called Acquire, and a request larger than the size is admitted when nothing else is held
+type Weighted struct {
+	lock    sync.Mutex
+	size    int64
+	cur     int64
+	waiters list.List
+}
+
+type waiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+// NewWeighted returns a semaphore with the given size
+func NewWeighted(size int64) *Weighted {
+	return &Weighted{size: size}
+}
+
+// Acquire blocks until n is available, or the context is canceled, in which case the context error is returned
+// and nothing is acquired
+func (s *Weighted) Acquire(ctx context.Context, n int64) error {
+	done := ctx.Done()
+	s.lock.Lock()
+	select {
+	case <-done:
+		s.lock.Unlock()
+		return ctx.Err()
+	default:
+	}
+	if s.fits(n) && s.waiters.Len() == 0 {
+		s.cur += n
+		s.lock.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	elem := s.waiters.PushBack(waiter{n: n, ready: ready})
+	s.lock.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-done:
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		select {
+		case <-ready:
+			// acquired after the context was canceled, release and report the cancellation
+			s.cur -= n
+		default:
+			s.waiters.Remove(elem)
+		}
+		s.notifyWaiters()
+		return ctx.Err()
+	}
+}
+
+// Release releases n, which must have been previously acquired
+func (s *Weighted) Release(n int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.cur -= n
+	if s.cur < 0 {
+		panic("semaphore: released more than held")
+	}
+	s.notifyWaiters()
+}
+
+// Resize changes the size of the semaphore; when reduced, holders are not affected but no more waiters are
+// admitted until enough has been released to fit in the new size
+func (s *Weighted) Resize(size int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.size = size
+	s.notifyWaiters()
+}
+
+// Size returns the current size of the semaphore
+func (s *Weighted) Size() int64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.size
+}
+
+// Held returns the amount currently acquired
+func (s *Weighted) Held() int64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.cur
+}
+
+// fits returns true if n can be acquired, must be called with the lock held
+func (s *Weighted) fits(n int64) bool {
+	return s.cur == 0 || s.cur+n <= s.size
+}
+
+// notifyWaiters admits waiters in order while they fit, must be called with the lock held
+func (s *Weighted) notifyWaiters() {
+	for {
+		next := s.waiters.Front()
+		if next == nil {
+			return
+		}
+		w := next.Value.(waiter)
+		if !s.fits(w.n) {
+			// waiters are admitted in order, so larger requests are not starved by smaller ones
+			return
+		}
+		s.cur += w.n
+		s.waiters.Remove(next)
+		close(w.ready)
+	}
+}
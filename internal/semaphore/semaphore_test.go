@@ -0,0 +1,100 @@
+package semaphore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Weighted(t *testing.T) {
+	s := NewWeighted(3)
+	ctx := context.Background()
+
+	require.NoError(t, s.Acquire(ctx, 2))
+	require.NoError(t, s.Acquire(ctx, 1))
+	require.Equal(t, int64(3), s.Held())
+
+	acquired := make(chan struct{})
+	go func() {
+		defer close(acquired)
+		require.NoError(t, s.Acquire(ctx, 2))
+	}()
+
+	select {
+	case <-acquired:
+		require.Fail(t, "should not acquire more than the size")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	s.Release(1)
+	select {
+	case <-acquired:
+		require.Fail(t, "should not acquire more than the size")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	s.Release(2)
+	<-acquired
+	require.Equal(t, int64(2), s.Held())
+}
+
+func Test_WeightedCanceled(t *testing.T) {
+	s := NewWeighted(1)
+	require.NoError(t, s.Acquire(context.Background(), 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, s.Acquire(ctx, 1), context.DeadlineExceeded)
+	require.Equal(t, int64(1), s.Held())
+
+	s.Release(1)
+	require.Equal(t, int64(0), s.Held())
+}
+
+func Test_WeightedOversized(t *testing.T) {
+	s := NewWeighted(2)
+	ctx := context.Background()
+
+	// requests larger than the size are admitted when nothing else is held
+	require.NoError(t, s.Acquire(ctx, 5))
+	require.Equal(t, int64(5), s.Held())
+	s.Release(5)
+}
+
+func Test_WeightedResize(t *testing.T) {
+	s := NewWeighted(1)
+	ctx := context.Background()
+	require.NoError(t, s.Acquire(ctx, 1))
+
+	acquired := make(chan struct{})
+	go func() {
+		defer close(acquired)
+		require.NoError(t, s.Acquire(ctx, 1))
+	}()
+
+	// growing admits waiters
+	s.Resize(2)
+	<-acquired
+	require.Equal(t, int64(2), s.Held())
+
+	// shrinking does not affect holders, but no more are admitted
+	s.Resize(1)
+	s.Release(1)
+
+	acquired = make(chan struct{})
+	go func() {
+		defer close(acquired)
+		require.NoError(t, s.Acquire(ctx, 1))
+	}()
+	select {
+	case <-acquired:
+		require.Fail(t, "should not acquire more than the new size")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	s.Release(1)
+	<-acquired
+	require.Equal(t, int64(1), s.Size())
+}
@@ -0,0 +1,69 @@
+package stats
+
+import (
+	"time"
+
+	"github.com/anchore/go-sync/internal/atomic"
+)
+
+// HistogramBuckets is the number of buckets in a Histogram
+const HistogramBuckets = 32
+
+// Histogram records durations in exponentially sized buckets, bucket i counts durations up to 1µs << i, and
+// the last bucket counts all larger durations
+type Histogram struct {
+	buckets [HistogramBuckets]atomic.Int64
+	count   atomic.Int64
+	sum     atomic.Int64
+	max     atomic.Int64
+}
+
+// BucketBound returns the inclusive upper bound of the bucket
+func BucketBound(bucket int) time.Duration {
+	if bucket >= HistogramBuckets-1 {
+		return time.Duration(1<<63 - 1)
+	}
+	return time.Microsecond << bucket
+}
+
+// Observe records the duration
+func (h *Histogram) Observe(d time.Duration) {
+	bucket := 0
+	for bucket < HistogramBuckets-1 && d > BucketBound(bucket) {
+		bucket++
+	}
+	h.buckets[bucket].Add(1)
+	h.count.Add(1)
+	h.sum.Add(int64(d))
+
+	m := h.max.Load()
+	for int64(d) > m {
+		if h.max.CompareAndSwap(m, int64(d)) {
+			break
+		}
+		m = h.max.Load()
+	}
+}
+
+// Count returns the number of durations observed
+func (h *Histogram) Count() int64 {
+	return h.count.Load()
+}
+
+// Sum returns the total of all durations observed
+func (h *Histogram) Sum() time.Duration {
+	return time.Duration(h.sum.Load())
+}
+
+// Max returns the largest duration observed
+func (h *Histogram) Max() time.Duration {
+	return time.Duration(h.max.Load())
+}
+
+// Bucket returns the number of durations observed in the bucket
+func (h *Histogram) Bucket(bucket int) int64 {
+	if bucket < 0 || bucket >= HistogramBuckets {
+		return 0
+	}
+	return h.buckets[bucket].Load()
+}
@@ -0,0 +1,33 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Histogram(t *testing.T) {
+	h := Histogram{}
+
+	h.Observe(0)
+	h.Observe(time.Microsecond)
+	h.Observe(3 * time.Microsecond)
+	h.Observe(time.Millisecond)
+	h.Observe(time.Hour)
+
+	require.Equal(t, int64(5), h.Count())
+	require.Equal(t, time.Hour, h.Max())
+	require.Equal(t, time.Hour+time.Millisecond+4*time.Microsecond, h.Sum())
+
+	// 0 and 1µs
+	require.Equal(t, int64(2), h.Bucket(0))
+	// 3µs is > 2µs and <= 4µs
+	require.Equal(t, int64(1), h.Bucket(2))
+	// 1ms is > 512µs and <= 1024µs
+	require.Equal(t, int64(1), h.Bucket(10))
+	require.Equal(t, int64(1), h.Bucket(HistogramBuckets-1))
+
+	require.Equal(t, time.Microsecond, BucketBound(0))
+	require.Equal(t, 1024*time.Microsecond, BucketBound(10))
+}
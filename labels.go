@@ -0,0 +1,84 @@
+package sync
+
+import (
+	"context"
+	"runtime/pprof"
+	"sync/atomic"
+)
+
+const (
+	// LabelExecutor is the pprof label set to the name an executor was registered with using SetContextExecutor
+	LabelExecutor = "executor"
+
+	// LabelTask is the pprof label set to the label provided to GoLabeled
+	LabelTask = "task"
+)
+
+// namedExecutor is implemented by executors which label the goroutines executing units of work with their name
+type namedExecutor interface {
+	executorName() string
+	// setExecutorName sets the name, unless the executor has already been named
+	setExecutorName(name string)
+}
+
+// GoLabeled adds the unit of work to the executor, setting the LabelTask pprof label while it executes, along with
+// the LabelExecutor label if the executor was registered with SetContextExecutor, so profiles can be broken down
+// by the kind of unit of work. Labels in the context, such as those added by the caller with pprof.Do, are kept
+func GoLabeled(ctx context.Context, executor Executor, label string, fn func()) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	labels := []string{LabelTask, label}
+	if e, ok := executor.(namedExecutor); ok {
+		if name := e.executorName(); name != "" {
+			labels = append(labels, LabelExecutor, name)
+		}
+	}
+	executor.Go(func() {
+		pprof.Do(ctx, pprof.Labels(labels...), func(context.Context) {
+			fn()
+		})
+	})
+}
+
+// nameExecutor sets the name used to label the executor's goroutines, if it supports labels and is not already
+// named, so an executor registered under several names keeps the first
+func nameExecutor(executor Executor, name string) {
+	if e, ok := executor.(namedExecutor); ok {
+		if name == ExecutorDefault {
+			name = "default"
+		}
+		e.setExecutorName(name)
+	}
+}
+
+// taskLabels sets the LabelExecutor pprof label on the goroutines executing units of work, once the executor has
+// been named by SetContextExecutor
+type taskLabels struct {
+	name atomic.Pointer[string]
+}
+
+func (l *taskLabels) executorName() string {
+	if name := l.name.Load(); name != nil {
+		return *name
+	}
+	return ""
+}
+
+func (l *taskLabels) setExecutorName(name string) {
+	l.name.CompareAndSwap(nil, &name)
+}
+
+// wrap returns a function executing fn with the executor label set, or fn when the executor is not named
+func (l *taskLabels) wrap(fn func()) func() {
+	name := l.name.Load()
+	if name == nil {
+		return fn
+	}
+	labels := pprof.Labels(LabelExecutor, *name)
+	return func() {
+		pprof.Do(context.Background(), labels, func(context.Context) {
+			fn()
+		})
+	}
+}
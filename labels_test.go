@@ -0,0 +1,122 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ExecutorLabels(t *testing.T) {
+	tests := []struct {
+		name     string
+		executor Executor
+	}{
+		{name: "unbounded", executor: NewExecutor(-1)},
+		{name: "bounded", executor: NewExecutor(2)},
+		{name: "queued", executor: NewQueuedExecutor(2)},
+		{name: "weighted", executor: NewWeightedExecutor(2)},
+		{name: "rate limited", executor: NewRateLimitedExecutor(2, 0, 1)},
+		{name: "adaptive", executor: NewAdaptiveExecutor(2, 4)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := SetContextExecutor(context.Background(), "io", test.executor)
+			e := ContextExecutor(&ctx, "io")
+
+			started := make(chan struct{}, 2)
+			release := make(chan struct{})
+			e.Go(func() {
+				started <- struct{}{}
+				<-release
+			})
+			GoLabeled(ctx, e, "parse", func() {
+				started <- struct{}{}
+				<-release
+			})
+			<-started
+			<-started
+
+			profile := goroutineProfile(t)
+			close(release)
+			e.Wait(context.Background())
+
+			require.Contains(t, profile, `labels: {"executor":"io"}`)
+			require.Contains(t, profile, `labels: {"executor":"io", "task":"parse"}`)
+		})
+	}
+}
+
+func Test_ExecutorLabelsChild(t *testing.T) {
+	ctx := SetContextExecutor(context.Background(), ExecutorDefault, NewExecutor(2))
+	e := ContextExecutor(&ctx, ExecutorDefault)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	e.Go(func() {
+		child := ContextExecutor(&ctx, ExecutorDefault)
+		GoLabeled(ctx, child, "nested", func() {
+			close(started)
+			<-release
+		})
+		child.Wait(context.Background())
+	})
+	<-started
+
+	profile := goroutineProfile(t)
+	close(release)
+	e.Wait(context.Background())
+
+	require.Contains(t, profile, `labels: {"executor":"default", "task":"nested"}`)
+}
+
+func Test_GoLabeledUnnamed(t *testing.T) {
+	e := NewExecutor(-1)
+
+	release := make(chan struct{})
+	GoLabeled(context.Background(), e, "unnamed", func() {
+		<-release
+	})
+	require.Eventually(t, func() bool {
+		return bytes.Contains([]byte(goroutineProfile(t)), []byte(`labels: {"task":"unnamed"}`))
+	}, time.Second, time.Millisecond)
+	close(release)
+	e.Wait(context.Background())
+}
+
+func Test_GoLabeledContextLabels(t *testing.T) {
+	e := NewExecutor(-1)
+	ctx := SetContextExecutor(context.Background(), "io", e)
+
+	release := make(chan struct{})
+	pprof.Do(ctx, pprof.Labels("request", "1"), func(ctx context.Context) {
+		GoLabeled(ctx, e, "parse", func() {
+			<-release
+		})
+	})
+	// the caller's labels are kept
+	require.Eventually(t, func() bool {
+		return bytes.Contains([]byte(goroutineProfile(t)), []byte(`labels: {"executor":"io", "request":"1", "task":"parse"}`))
+	}, time.Second, time.Millisecond)
+	close(release)
+	e.Wait(context.Background())
+}
+
+func Test_SetContextExecutorNamesOnce(t *testing.T) {
+	e := NewExecutor(-1)
+	ctx := SetContextExecutor(context.Background(), "io", e)
+	ctx = SetContextExecutor(ctx, "cpu", e)
+
+	// registering under another name does not relabel the executor
+	require.Equal(t, "io", e.(namedExecutor).executorName())
+	require.Same(t, e, ContextExecutor(&ctx, "cpu"))
+}
+
+func goroutineProfile(t *testing.T) string {
+	buf := bytes.Buffer{}
+	require.NoError(t, pprof.Lookup("goroutine").WriteTo(&buf, 1))
+	return buf.String()
+}
@@ -20,18 +20,41 @@ type Locking struct {
 
 var _ Lockable = (*Locking)(nil)
 
+// Lock acquires an exclusive lock, this is a yield point for units of work executed by NewFuzzExecutor
 func (l *Locking) Lock() (unlock UnlockFunc) {
+	if t := currentFuzzTask(); t != nil {
+		t.acquire(l.lock.Lock)
+		return l.fuzzUnlock
+	}
 	l.lock.Lock()
 	return l.lock.Unlock
 }
 
+// RLock acquires a read lock, this is a yield point for units of work executed by NewFuzzExecutor
 func (l *Locking) RLock() (unlock UnlockFunc) {
+	if t := currentFuzzTask(); t != nil {
+		t.acquire(l.lock.RLock)
+		return l.fuzzRUnlock
+	}
 	l.lock.RLock()
 	return l.lock.RUnlock
 }
 
 func (l *Locking) IsExclusiveLock(unlockFunc UnlockFunc) (exclusive bool) {
-	unlock := reflect.ValueOf(l.lock.Unlock).Pointer()
 	f := reflect.ValueOf(unlockFunc).Pointer()
-	return unlock == f
+	return f == reflect.ValueOf(l.lock.Unlock).Pointer() || f == reflect.ValueOf(l.fuzzUnlock).Pointer()
+}
+
+func (l *Locking) fuzzUnlock() {
+	l.lock.Unlock()
+	if t := currentFuzzTask(); t != nil {
+		t.release()
+	}
+}
+
+func (l *Locking) fuzzRUnlock() {
+	l.lock.RUnlock()
+	if t := currentFuzzTask(); t != nil {
+		t.release()
+	}
 }
@@ -0,0 +1,145 @@
+package sync
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/anchore/go-sync/internal/atomic"
+)
+
+// RateLimiter is a token bucket rate limiter: tokens are added at a fixed rate up to the burst size, and each
+// call to Wait or Allow consumes one token
+type RateLimiter struct {
+	clock  Clock
+	rate   float64
+	burst  float64
+	lock   sync.Mutex
+	tokens float64
+	last   time.Time
+	// statistics
+	allowedCount   atomic.Int64
+	throttledCount atomic.Int64
+	throttledTime  atomic.Int64
+}
+
+// RateLimiterStats provides statistics about the time spent waiting for a RateLimiter
+type RateLimiterStats struct {
+	// Allowed is the number of tokens acquired
+	Allowed int64
+
+	// Throttled is the number of tokens which were not immediately available
+	Throttled int64
+
+	// ThrottledTime is the total time spent waiting for tokens
+	ThrottledTime time.Duration
+}
+
+// NewRateLimiter returns a RateLimiter allowing perSecond tokens per second on average, with up to burst tokens
+// available at once. A perSecond <= 0 does not limit, and a burst < 1 is treated as 1. Only the WithClock option
+// applies to a RateLimiter
+func NewRateLimiter(perSecond float64, burst int, opts ...Option) *RateLimiter {
+	cfg := newExecutorConfig(opts...)
+	clock := cfg.getClock()
+	burst = max(burst, 1)
+	return &RateLimiter{
+		clock:  clock,
+		rate:   perSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   clock.Now(),
+	}
+}
+
+// Allow consumes a token and returns true if one is immediately available, otherwise returns false
+func (r *RateLimiter) Allow() bool {
+	if r.rate <= 0 {
+		r.allowed(0)
+		return true
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.refill()
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	r.allowed(0)
+	return true
+}
+
+// Wait blocks until a token is available, returning the context error if the context is canceled first
+func (r *RateLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	delay := r.reserve()
+	if delay <= 0 {
+		r.allowed(0)
+		return nil
+	}
+
+	timer := r.clock.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		r.allowed(delay)
+		return nil
+	case <-ctx.Done():
+		r.cancel()
+		return ctx.Err()
+	}
+}
+
+// Stats returns statistics about tokens acquired from this RateLimiter
+func (r *RateLimiter) Stats() RateLimiterStats {
+	return RateLimiterStats{
+		Allowed:       r.allowedCount.Load(),
+		Throttled:     r.throttledCount.Load(),
+		ThrottledTime: time.Duration(r.throttledTime.Load()),
+	}
+}
+
+// reserve consumes a token, returning the time to wait until it is available
+func (r *RateLimiter) reserve() time.Duration {
+	if r.rate <= 0 {
+		return 0
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.refill()
+	r.tokens--
+	if r.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-r.tokens / r.rate * float64(time.Second))
+}
+
+// cancel returns a reserved token which was not used
+func (r *RateLimiter) cancel() {
+	if r.rate <= 0 {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.refill()
+	r.tokens = min(r.tokens+1, r.burst)
+}
+
+// refill adds tokens for the time elapsed since the last refill, must be called with the lock held
+func (r *RateLimiter) refill() {
+	now := r.clock.Now()
+	elapsed := now.Sub(r.last)
+	r.last = now
+	if elapsed > 0 {
+		r.tokens = min(r.tokens+elapsed.Seconds()*r.rate, r.burst)
+	}
+}
+
+func (r *RateLimiter) allowed(throttled time.Duration) {
+	r.allowedCount.Add(1)
+	if throttled > 0 {
+		r.throttledCount.Add(1)
+		r.throttledTime.Add(int64(throttled))
+	}
+}
@@ -0,0 +1,75 @@
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RateLimiterAllow(t *testing.T) {
+	r := NewRateLimiter(1, 3)
+
+	// burst tokens are immediately available
+	require.True(t, r.Allow())
+	require.True(t, r.Allow())
+	require.True(t, r.Allow())
+	require.False(t, r.Allow())
+
+	stats := r.Stats()
+	require.Equal(t, int64(3), stats.Allowed)
+	require.Equal(t, int64(0), stats.Throttled)
+}
+
+func Test_RateLimiterWait(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	r := NewRateLimiter(100, 1, WithClock(clock))
+
+	// the first token is immediately available
+	require.NoError(t, r.Wait(context.Background()))
+
+	for range 5 {
+		waited := make(chan error)
+		go func() {
+			waited <- r.Wait(context.Background())
+		}()
+		require.NoError(t, clock.BlockUntil(context.Background(), 1))
+		select {
+		case <-waited:
+			t.Fatal("token acquired before it was available")
+		default:
+		}
+		clock.Advance(10 * time.Millisecond)
+		require.NoError(t, <-waited)
+	}
+
+	stats := r.Stats()
+	require.Equal(t, int64(6), stats.Allowed)
+	require.Equal(t, int64(5), stats.Throttled)
+	require.Equal(t, 50*time.Millisecond, stats.ThrottledTime)
+}
+
+func Test_RateLimiterWaitCanceled(t *testing.T) {
+	r := NewRateLimiter(0.001, 1)
+	require.NoError(t, r.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, r.Wait(ctx), context.DeadlineExceeded)
+
+	// the canceled reservation is returned, so the next caller is not waiting on it
+	r.lock.Lock()
+	tokens := r.tokens
+	r.lock.Unlock()
+	require.Greater(t, tokens, -1.0)
+}
+
+func Test_RateLimiterUnlimited(t *testing.T) {
+	r := NewRateLimiter(0, 0)
+	for range 1000 {
+		require.True(t, r.Allow())
+		require.NoError(t, r.Wait(context.Background()))
+	}
+	require.Equal(t, int64(2000), r.Stats().Allowed)
+}
@@ -0,0 +1,267 @@
+package sync
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// Schedule determines when a function added to a Scheduler executes
+type Schedule interface {
+	// Next returns the next time to execute after the provided time, or the zero time if there are no more
+	Next(after time.Time) time.Time
+}
+
+// Interval returns a Schedule which executes repeatedly, each interval after the previous time
+func Interval(interval time.Duration) Schedule {
+	return intervalSchedule{interval: max(interval, 1)}
+}
+
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+func (s intervalSchedule) Next(after time.Time) time.Time {
+	return after.Add(s.interval)
+}
+
+// onceSchedule executes a single time. The first call to Next returns the time even if it has passed, such as
+// for a delay <= 0 or one which elapsed before the function was added, so the function always executes once
+type onceSchedule struct {
+	at    time.Time
+	fired bool
+}
+
+func (s *onceSchedule) Next(time.Time) time.Time {
+	if s.fired {
+		return time.Time{}
+	}
+	s.fired = true
+	return s.at
+}
+
+// Scheduler executes functions after a delay or according to a Schedule, dispatching them to an Executor
+type Scheduler struct {
+	executor Executor
+	clock    Clock
+	lock     sync.Mutex
+	entries  scheduleHeap
+	seq      uint64
+	wake     chan struct{}
+	running  bool
+	stopped  bool
+}
+
+// ScheduledTask is a handle to a function added to a Scheduler
+type ScheduledTask struct {
+	scheduler *Scheduler
+	schedule  Schedule
+	fn        func()
+	at        time.Time
+	seq       uint64
+	index     int
+	done      chan struct{}
+}
+
+// NewScheduler returns a Scheduler which dispatches functions to the executor when they are due. The executor
+// determines how many functions execute concurrently; with a blocking executor, functions which are due are
+// delayed until the executor accepts them. Use WithClock to control time in tests
+func NewScheduler(executor Executor, opts ...Option) *Scheduler {
+	cfg := newExecutorConfig(opts...)
+	return &Scheduler{
+		executor: executor,
+		clock:    cfg.getClock(),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// After executes the function once after the delay, a delay <= 0 executes the function as soon as possible
+func (s *Scheduler) After(delay time.Duration, fn func()) *ScheduledTask {
+	return s.add(&onceSchedule{at: s.clock.Now().Add(delay)}, fn)
+}
+
+// Every executes the function repeatedly, each interval, starting one interval from now
+func (s *Scheduler) Every(interval time.Duration, fn func()) *ScheduledTask {
+	return s.Schedule(Interval(interval), fn)
+}
+
+// Schedule executes the function at each time returned by the Schedule, such as one returned from Cron. When
+// executions are missed, such as when the executor blocks, the function executes once and the schedule
+// continues from the current time
+func (s *Scheduler) Schedule(schedule Schedule, fn func()) *ScheduledTask {
+	return s.add(schedule, fn)
+}
+
+// Stop cancels all scheduled functions, functions already dispatched to the executor are not affected
+func (s *Scheduler) Stop() {
+	s.lock.Lock()
+	s.stopped = true
+	entries := s.entries
+	for _, t := range entries {
+		t.index = -1
+	}
+	s.entries = nil
+	s.lock.Unlock()
+
+	for _, t := range entries {
+		close(t.done)
+	}
+	s.notify()
+}
+
+// Cancel stops any further executions of the function, returning false if the function will not execute again
+func (t *ScheduledTask) Cancel() bool {
+	s := t.scheduler
+	s.lock.Lock()
+	if t.index < 0 {
+		s.lock.Unlock()
+		return false
+	}
+	heap.Remove(&s.entries, t.index)
+	s.lock.Unlock()
+
+	close(t.done)
+	s.notify()
+	return true
+}
+
+// Done returns a channel which is closed when the function will not execute again, because the Schedule has no
+// more times or it was canceled
+func (t *ScheduledTask) Done() <-chan struct{} {
+	return t.done
+}
+
+// Next returns the next time the function will execute, or the zero time if it will not execute again
+func (t *ScheduledTask) Next() time.Time {
+	t.scheduler.lock.Lock()
+	defer t.scheduler.lock.Unlock()
+	if t.index < 0 {
+		return time.Time{}
+	}
+	return t.at
+}
+
+func (s *Scheduler) add(schedule Schedule, fn func()) *ScheduledTask {
+	t := &ScheduledTask{
+		scheduler: s,
+		schedule:  schedule,
+		fn:        fn,
+		index:     -1,
+		done:      make(chan struct{}),
+	}
+
+	s.lock.Lock()
+	t.at = schedule.Next(s.clock.Now())
+	if s.stopped || t.at.IsZero() {
+		s.lock.Unlock()
+		close(t.done)
+		return t
+	}
+	s.seq++
+	t.seq = s.seq
+	heap.Push(&s.entries, t)
+	start := !s.running
+	s.running = true
+	s.lock.Unlock()
+
+	if start {
+		go s.run()
+	} else {
+		s.notify()
+	}
+	return t
+}
+
+// notify wakes the scheduling goroutine to observe changes to the scheduled functions
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run dispatches functions as they become due, exiting when nothing is scheduled
+func (s *Scheduler) run() {
+	for {
+		fn, wait, ok := s.next()
+		if !ok {
+			return
+		}
+		if fn != nil {
+			s.executor.Go(fn)
+			continue
+		}
+		timer := s.clock.NewTimer(wait)
+		select {
+		case <-timer.C():
+		case <-s.wake:
+			timer.Stop()
+		}
+	}
+}
+
+// next returns the next function which is due, or the time to wait until the next function is due, or false
+// when nothing is scheduled and the scheduling goroutine should exit
+func (s *Scheduler) next() (func(), time.Duration, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.entries) == 0 {
+		s.running = false
+		return nil, 0, false
+	}
+	t := s.entries[0]
+	now := s.clock.Now()
+	if t.at.After(now) {
+		return nil, t.at.Sub(now), true
+	}
+
+	next := t.schedule.Next(t.at)
+	if !next.IsZero() && !next.After(now) {
+		// executions were missed, continue from now
+		next = t.schedule.Next(now)
+	}
+	if next.IsZero() {
+		heap.Pop(&s.entries)
+		close(t.done)
+	} else {
+		t.at = next
+		heap.Fix(&s.entries, t.index)
+	}
+	return t.fn, 0, true
+}
+
+// scheduleHeap orders scheduled functions by the next time to execute, then the order they were added
+type scheduleHeap []*ScheduledTask
+
+func (h scheduleHeap) Len() int {
+	return len(h)
+}
+
+func (h scheduleHeap) Less(i, j int) bool {
+	if h[i].at.Equal(h[j].at) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].at.Before(h[j].at)
+}
+
+func (h scheduleHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *scheduleHeap) Push(x any) {
+	t, _ := x.(*ScheduledTask)
+	t.index = len(*h)
+	*h = append(*h, t)
+}
+
+func (h *scheduleHeap) Pop() any {
+	old := *h
+	n := len(old)
+	t := old[n-1]
+	old[n-1] = nil
+	t.index = -1
+	*h = old[:n-1]
+	return t
+}
@@ -0,0 +1,155 @@
+package sync
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SchedulerAfter(t *testing.T) {
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler(NewExecutor(0), WithClock(clock))
+
+	executed := make(chan time.Time, 1)
+	task := s.After(time.Second, func() {
+		executed <- clock.Now()
+	})
+	require.Equal(t, start.Add(time.Second), task.Next())
+
+	require.NoError(t, clock.BlockUntil(context.Background(), 1))
+	clock.Advance(time.Second)
+	require.Equal(t, start.Add(time.Second), <-executed)
+	<-task.Done()
+	require.True(t, task.Next().IsZero())
+	require.False(t, task.Cancel())
+}
+
+func Test_SchedulerAfterElapsed(t *testing.T) {
+	clock := NewFakeClock(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+	s := NewScheduler(NewExecutor(0), WithClock(clock))
+
+	// delays which have already elapsed execute without advancing the clock
+	executed := make(chan struct{}, 2)
+	for _, delay := range []time.Duration{0, -time.Second} {
+		task := s.After(delay, func() {
+			executed <- struct{}{}
+		})
+		<-executed
+		<-task.Done()
+	}
+
+	// also with the system clock
+	task := NewScheduler(NewExecutor(0)).After(0, func() {
+		executed <- struct{}{}
+	})
+	<-executed
+	<-task.Done()
+}
+
+func Test_SchedulerEvery(t *testing.T) {
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler(NewExecutor(0), WithClock(clock))
+
+	executed := make(chan time.Time)
+	task := s.Every(time.Minute, func() {
+		executed <- clock.Now()
+	})
+	for i := range 3 {
+		require.NoError(t, clock.BlockUntil(context.Background(), 1))
+		clock.Advance(time.Minute)
+		require.Equal(t, start.Add(time.Duration(i+1)*time.Minute), <-executed)
+	}
+
+	// missed executions are skipped, continuing from the current time
+	require.NoError(t, clock.BlockUntil(context.Background(), 1))
+	clock.Advance(5 * time.Minute)
+	require.Equal(t, start.Add(8*time.Minute), <-executed)
+	require.Equal(t, start.Add(9*time.Minute), task.Next())
+
+	require.True(t, task.Cancel())
+	<-task.Done()
+	// the scheduling goroutine stops its timer and exits
+	require.Eventually(t, func() bool {
+		return clock.Timers() == 0
+	}, time.Second, time.Millisecond)
+}
+
+func Test_SchedulerCron(t *testing.T) {
+	start := time.Date(2025, time.January, 1, 8, 30, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler(NewExecutor(0), WithClock(clock))
+	defer s.Stop()
+
+	executed := make(chan time.Time)
+	task := s.Schedule(MustCron("0 9 * * *"), func() {
+		executed <- clock.Now()
+	})
+	require.Equal(t, time.Date(2025, time.January, 1, 9, 0, 0, 0, time.UTC), task.Next())
+
+	require.NoError(t, clock.BlockUntil(context.Background(), 1))
+	clock.Advance(30 * time.Minute)
+	require.Equal(t, time.Date(2025, time.January, 1, 9, 0, 0, 0, time.UTC), <-executed)
+	require.Equal(t, time.Date(2025, time.January, 2, 9, 0, 0, 0, time.UTC), task.Next())
+}
+
+func Test_SchedulerCancel(t *testing.T) {
+	s := NewScheduler(NewExecutor(-1))
+
+	executed := atomic.Bool{}
+	later := s.After(time.Hour, func() {
+		executed.Store(true)
+	})
+	soon := make(chan struct{})
+	s.After(time.Millisecond, func() {
+		close(soon)
+	})
+
+	require.True(t, later.Cancel())
+	require.False(t, later.Cancel())
+	<-soon
+	require.False(t, executed.Load())
+}
+
+func Test_SchedulerStop(t *testing.T) {
+	s := NewScheduler(NewExecutor(-1))
+
+	executed := atomic.Bool{}
+	task := s.After(10*time.Millisecond, func() {
+		executed.Store(true)
+	})
+	s.Stop()
+	<-task.Done()
+
+	// functions added after stopping are not scheduled
+	after := s.Every(time.Millisecond, func() {
+		executed.Store(true)
+	})
+	<-after.Done()
+
+	time.Sleep(20 * time.Millisecond)
+	require.False(t, executed.Load())
+}
+
+func Test_SchedulerOrder(t *testing.T) {
+	s := NewScheduler(NewExecutor(0))
+
+	executed := List[int]{}
+	done := make(chan struct{})
+	s.After(3*time.Millisecond, func() {
+		executed.Append(3)
+		close(done)
+	})
+	s.After(time.Millisecond, func() {
+		executed.Append(1)
+	})
+	s.After(2*time.Millisecond, func() {
+		executed.Append(2)
+	})
+	<-done
+	require.Equal(t, []int{1, 2, 3}, executed.Values())
+}
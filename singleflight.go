@@ -0,0 +1,142 @@
+package sync
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SingleFlight coalesces concurrent calls for the same key, so the function provided is executed once and the
+// result shared with all callers. The zero value is ready to use, executing functions with the default context
+// executor and not caching results
+type SingleFlight[K comparable, V any] struct {
+	// ExecutorName is the name of the context executor used to execute functions
+	ExecutorName string
+
+	// TTL is how long successful results are cached after completing, results are not cached when TTL <= 0
+	TTL time.Duration
+
+	// Clock is used to expire cached results, the system clock is used when nil
+	Clock Clock
+
+	lock    sync.Mutex
+	flights map[K]*flight[V]
+	// sweep is when expired results are next removed, so results for keys which are not requested again are not
+	// retained indefinitely
+	sweep time.Time
+}
+
+// flight is a call in progress or a cached result for a key of a SingleFlight
+type flight[V any] struct {
+	future  *future[V]
+	expires time.Time
+}
+
+// Do returns the result of calling fn for the key. If a call for the key is in progress or a cached result is
+// available, the result is shared rather than calling fn again, and shared is true. The function is executed
+// with the named context executor and receives a context which is not canceled when the caller's context is,
+// since the result may be shared. If the caller's context is canceled before the result is available, the
+// context error is returned and the call continues for any other callers. If the executor does not execute the
+// function, such as when it has been closed, the reason is returned to all callers, such as ErrExecutorClosed, and
+// the next call executes the function again
+func (s *SingleFlight[K, V]) Do(ctx context.Context, key K, fn func(context.Context) (V, error)) (value V, shared bool, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	f, shared := s.flight(ctx, key, fn)
+	value, err = f.future.Get(ctx)
+	return value, shared, err
+}
+
+// DoFuture is like Do, returning a Future with the result rather than waiting for it
+func (s *SingleFlight[K, V]) DoFuture(ctx context.Context, key K, fn func(context.Context) (V, error)) Future[V] {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	f, _ := s.flight(ctx, key, fn)
+	return f.future
+}
+
+// Forget discards any cached result or call in progress for the key, so the next call executes the function.
+// Callers waiting for a call in progress still receive its result
+func (s *SingleFlight[K, V]) Forget(key K) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.flights, key)
+}
+
+// flight returns the existing flight for the key, or starts a new flight
+func (s *SingleFlight[K, V]) flight(ctx context.Context, key K, fn func(context.Context) (V, error)) (*flight[V], bool) {
+	s.lock.Lock()
+	s.removeExpired()
+	if f := s.flights[key]; f != nil && !s.expired(f) {
+		s.lock.Unlock()
+		return f, true
+	}
+	if s.flights == nil {
+		s.flights = map[K]*flight[V]{}
+	}
+	f := &flight[V]{future: newFuture[V]()}
+	s.flights[key] = f
+	s.lock.Unlock()
+
+	ctx = context.WithoutCancel(ctx)
+	executor := ContextExecutor(&ctx, s.ExecutorName)
+	goSkip(executor, func() {
+		value, err := call(func() (V, error) {
+			return fn(ctx)
+		})
+		s.complete(key, f, err)
+		f.future.complete(value, err)
+	}, func(err error) {
+		var zero V
+		s.complete(key, f, err)
+		f.future.complete(zero, err)
+	})
+	return f, false
+}
+
+// complete caches the result of the flight, or removes it if it failed or caching is disabled
+func (s *SingleFlight[K, V]) complete(key K, f *flight[V], err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.flights[key] != f {
+		// forgotten
+		return
+	}
+	if err != nil || s.TTL <= 0 {
+		delete(s.flights, key)
+		return
+	}
+	f.expires = s.now().Add(s.TTL)
+}
+
+// removeExpired removes expired results, at most once per TTL, must be called with the lock held
+func (s *SingleFlight[K, V]) removeExpired() {
+	if s.TTL <= 0 {
+		return
+	}
+	now := s.now()
+	if now.Before(s.sweep) {
+		return
+	}
+	s.sweep = now.Add(s.TTL)
+	for key, f := range s.flights {
+		if s.expired(f) {
+			delete(s.flights, key)
+		}
+	}
+}
+
+// expired returns true when the flight has a cached result past its TTL, must be called with the lock held
+func (s *SingleFlight[K, V]) expired(f *flight[V]) bool {
+	return !f.expires.IsZero() && !s.now().Before(f.expires)
+}
+
+// now returns the current time from the configured Clock
+func (s *SingleFlight[K, V]) now() time.Time {
+	if s.Clock == nil {
+		return time.Now()
+	}
+	return s.Clock.Now()
+}
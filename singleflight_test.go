@@ -0,0 +1,168 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SingleFlight(t *testing.T) {
+	ctx := SetContextExecutor(context.Background(), "io", NewExecutor(-1))
+	s := SingleFlight[string, int]{ExecutorName: "io"}
+
+	calls := atomic.Int32{}
+	release := make(chan struct{})
+	fn := func(context.Context) (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	// the first call starts the flight, so subsequent calls are shared while it is in progress
+	var futures []Future[int]
+	for range 10 {
+		futures = append(futures, s.DoFuture(ctx, "key", fn))
+	}
+	close(release)
+
+	for _, future := range futures {
+		value, err := future.Get(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 42, value)
+	}
+	require.Equal(t, int32(1), calls.Load())
+
+	// not cached without a TTL
+	_, shared, err := s.Do(ctx, "key", fn)
+	require.NoError(t, err)
+	require.False(t, shared)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func Test_SingleFlightZeroValue(t *testing.T) {
+	var s SingleFlight[int, string]
+	value, shared, err := s.Do(context.Background(), 1, func(context.Context) (string, error) {
+		return "value", nil
+	})
+	require.NoError(t, err)
+	require.False(t, shared)
+	require.Equal(t, "value", value)
+}
+
+func Test_SingleFlightErrors(t *testing.T) {
+	s := SingleFlight[string, int]{TTL: time.Hour}
+
+	calls := 0
+	_, _, err := s.Do(context.Background(), "key", func(context.Context) (int, error) {
+		calls++
+		return 0, fmt.Errorf("failed")
+	})
+	require.Error(t, err)
+
+	// errors are not cached
+	_, _, err = s.Do(context.Background(), "key", func(context.Context) (int, error) {
+		calls++
+		panic("panicked")
+	})
+	var p PanicError
+	require.ErrorAs(t, err, &p)
+	require.Equal(t, 2, calls)
+}
+
+func Test_SingleFlightTTL(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	s := SingleFlight[string, int]{TTL: 10 * time.Millisecond, Clock: clock}
+
+	calls := 0
+	fn := func(context.Context) (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	value, shared, err := s.Do(context.Background(), "key", fn)
+	require.NoError(t, err)
+	require.False(t, shared)
+	require.Equal(t, 1, value)
+
+	value, shared, err = s.Do(context.Background(), "key", fn)
+	require.NoError(t, err)
+	require.True(t, shared)
+	require.Equal(t, 1, value)
+
+	clock.Advance(10 * time.Millisecond)
+	value, shared, _ = s.Do(context.Background(), "key", fn)
+	require.False(t, shared)
+	require.Equal(t, 2, value)
+
+	s.Forget("key")
+	value, _, _ = s.Do(context.Background(), "key", fn)
+	require.Equal(t, 3, value)
+}
+
+func Test_SingleFlightExpiredRemoved(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	s := SingleFlight[string, int]{TTL: 10 * time.Millisecond, Clock: clock}
+	fn := func(context.Context) (int, error) {
+		return 1, nil
+	}
+
+	for _, key := range []string{"a", "b"} {
+		_, _, err := s.Do(context.Background(), key, fn)
+		require.NoError(t, err)
+	}
+	require.Len(t, s.flights, 2)
+
+	// expired results are removed even when their keys are not requested again
+	clock.Advance(10 * time.Millisecond)
+	_, _, err := s.Do(context.Background(), "c", fn)
+	require.NoError(t, err)
+	require.Len(t, s.flights, 1)
+	require.Contains(t, s.flights, "c")
+}
+
+func Test_SingleFlightSkipped(t *testing.T) {
+	e := NewExecutor(-1)
+	require.NoError(t, e.(ShutdownExecutor).Close())
+	ctx := SetContextExecutor(context.Background(), "", e)
+	s := SingleFlight[string, int]{TTL: time.Hour}
+
+	// functions which are not executed report the reason, and are not shared with later calls
+	for range 2 {
+		_, shared, err := s.Do(ctx, "key", func(context.Context) (int, error) {
+			return 1, nil
+		})
+		require.ErrorIs(t, err, ErrExecutorClosed)
+		require.False(t, shared)
+	}
+	require.Empty(t, s.flights)
+}
+
+func Test_SingleFlightCanceled(t *testing.T) {
+	ctx := SetContextExecutor(context.Background(), "", NewExecutor(-1))
+	s := SingleFlight[string, int]{}
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	future := s.DoFuture(ctx, "key", func(ctx context.Context) (int, error) {
+		close(started)
+		<-release
+		// not canceled by the caller's context
+		return 1, ctx.Err()
+	})
+	<-started
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, shared, err := s.Do(canceled, "key", nil)
+	require.ErrorIs(t, err, context.Canceled)
+	require.True(t, shared)
+
+	close(release)
+	value, err := future.Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+}
@@ -43,17 +43,17 @@ func ToSeq2[K comparable, V any](values map[K]V) iter.Seq2[K, V] {
 	}
 }
 
-// keyValue is used to for Seq2 and related sequence conversions
-type keyValue[K, V any] struct {
+// KeyValue is used for Seq2 and related sequence conversions, such as the Value of an ItemError returned by Collect2
+type KeyValue[K, V any] struct {
 	Key   K
 	Value V
 }
 
-// toKeyValueIterator converts an iter.Seq2[K,V] to an iter.Seq[keyValue[K,V]]
-func toKeyValueIterator[From1, From2 any](iterator iter.Seq2[From1, From2]) iter.Seq[keyValue[From1, From2]] {
-	return func(yield func(keyValue[From1, From2]) bool) {
+// toKeyValueIterator converts an iter.Seq2[K,V] to an iter.Seq[KeyValue[K,V]]
+func toKeyValueIterator[From1, From2 any](iterator iter.Seq2[From1, From2]) iter.Seq[KeyValue[From1, From2]] {
+	return func(yield func(KeyValue[From1, From2]) bool) {
 		for key, value := range iterator {
-			if !yield(keyValue[From1, From2]{Key: key, Value: value}) {
+			if !yield(KeyValue[From1, From2]{Key: key, Value: value}) {
 				return
 			}
 		}
@@ -46,7 +46,7 @@ func Test_ToIndexSeq(t *testing.T) {
 }
 
 // keyValueSeqToMap converts an iter.Seq[KeyValue[K,V]] to a map[K]V
-func keyValueSeqToMap[K comparable, V any](values iter.Seq[keyValue[K, V]]) map[K]V {
+func keyValueSeqToMap[K comparable, V any](values iter.Seq[KeyValue[K, V]]) map[K]V {
 	out := map[K]V{}
 	for kv := range values {
 		out[kv.Key] = kv.Value